@@ -0,0 +1,170 @@
+package telegraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AccountManager tracks Telegraph accounts by short name
+//
+// Telegraph does not require short names to be unique, so creating accounts in
+// bulk can easily produce confusing duplicates. AccountManager guards against this
+// locally: CreateAccountIfAbsent only creates an account for a short name it has
+// not seen before. It is safe for concurrent use, and no lock is held while an
+// account is being created, so a slow call does not hold up other names.
+//
+// Example:
+//
+//	manager := client.NewAccountManager()
+//	account, created, err := manager.CreateAccountIfAbsent(ctx, &telegraph.CreateAccountRequest{ShortName: "Sandbox"})
+type AccountManager struct {
+	client   *Client
+	mu       sync.Mutex
+	accounts map[string]*Account
+	// pending holds a channel per short name being created, closed once the call returns
+	pending map[string]chan struct{}
+}
+
+// NewAccountManager creates an account manager that creates accounts with the client
+func (c *Client) NewAccountManager() *AccountManager {
+	return &AccountManager{
+		client:   c,
+		accounts: make(map[string]*Account),
+		pending:  make(map[string]chan struct{}),
+	}
+}
+
+// Add registers an existing account, e.g. one created in an earlier run, under
+// its short name. A nil account is ignored.
+func (m *AccountManager) Add(account *Account) {
+	if account == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts[account.ShortName] = account
+}
+
+// Account returns the account registered under shortName, if any
+func (m *AccountManager) Account(shortName string) (*Account, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, ok := m.accounts[shortName]
+	return account, ok
+}
+
+// Accounts returns every registered account, sorted by short name
+func (m *AccountManager) Accounts() []*Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accounts := make([]*Account, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ShortName < accounts[j].ShortName
+	})
+	return accounts
+}
+
+// CreateAccountIfAbsent returns the account registered under req.ShortName, or
+// creates and registers a new one if there is none. created reports whether a new
+// account was created. Concurrent calls with the same short name create a single
+// account: the others wait for it, and try again themselves if it fails.
+func (m *AccountManager) CreateAccountIfAbsent(ctx context.Context, req *CreateAccountRequest) (account *Account, created bool, err error) {
+	if req == nil {
+		return nil, false, ErrNilRequest
+	}
+	for {
+		m.mu.Lock()
+		if account, ok := m.accounts[req.ShortName]; ok {
+			m.mu.Unlock()
+			return account, false, nil
+		}
+		wait, inFlight := m.pending[req.ShortName]
+		if !inFlight {
+			break // with the lock held
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+
+	// Reserve the short name, then create the account without holding the lock
+	done := make(chan struct{})
+	m.pending[req.ShortName] = done
+	m.mu.Unlock()
+
+	account, err = m.client.CreateAccount(ctx, req)
+
+	m.mu.Lock()
+	delete(m.pending, req.ShortName)
+	if err == nil {
+		m.accounts[req.ShortName] = account
+	}
+	m.mu.Unlock()
+	close(done)
+
+	if err != nil {
+		return nil, false, err
+	}
+	return account, true, nil
+}
+
+// Bootstrap creates an account and its first page in one call
+//
+// If firstPage has no access token, the new account's token is used. The page
+// request is validated before the account is created, so invalid input does
+// not leave an unused account behind. Telegraph cannot delete accounts, so if
+// the page cannot be created, Bootstrap returns the new account together with
+// the error, and the caller can retry the page with account.AccessToken.
+// firstPage itself is not modified.
+//
+// Example:
+//
+//	account, page, err := client.Bootstrap(ctx,
+//		&telegraph.CreateAccountRequest{ShortName: "MyChannel"},
+//		&telegraph.CreatePageRequest{Title: "Welcome", Content: content},
+//	)
+func (c *Client) Bootstrap(ctx context.Context, acct *CreateAccountRequest, firstPage *CreatePageRequest) (*Account, *Page, error) {
+	if acct == nil || firstPage == nil {
+		return nil, nil, ErrNilRequest
+	}
+	if err := acct.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	pageReq := *firstPage
+	validate := pageReq.Validate
+	if pageReq.AccessToken == "" {
+		// Validate everything but the token, which the account does not have yet
+		validate = pageReq.validateContentFields
+	}
+	if err := validate(); err != nil {
+		return nil, nil, fmt.Errorf("first page: %w", err)
+	}
+
+	account, err := c.CreateAccount(ctx, acct)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pageReq.AccessToken == "" {
+		pageReq.AccessToken = account.AccessToken
+	}
+	page, err := c.CreatePage(ctx, &pageReq)
+	if err != nil {
+		return account, nil, fmt.Errorf("account %q created, but first page failed: %w", account.ShortName, err)
+	}
+	return account, page, nil
+}
@@ -0,0 +1,203 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountManager(t *testing.T) {
+	created := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateAccountRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		created++
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{ShortName: req.ShortName, AccessToken: "token-" + req.ShortName}})
+	}))
+	defer server.Close()
+
+	manager := NewClient(WithBaseURL(server.URL)).NewAccountManager()
+	manager.Add(&Account{ShortName: "Existing", AccessToken: "existing-token"})
+
+	t.Run("absent", func(t *testing.T) {
+		account, ok, err := manager.CreateAccountIfAbsent(context.Background(), &CreateAccountRequest{ShortName: "Sandbox"})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "token-Sandbox", account.AccessToken)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("present", func(t *testing.T) {
+		account, ok, err := manager.CreateAccountIfAbsent(context.Background(), &CreateAccountRequest{ShortName: "Sandbox"})
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "token-Sandbox", account.AccessToken)
+
+		account, ok, err = manager.CreateAccountIfAbsent(context.Background(), &CreateAccountRequest{ShortName: "Existing"})
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "existing-token", account.AccessToken)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("invalid request is not registered", func(t *testing.T) {
+		_, _, err := manager.CreateAccountIfAbsent(context.Background(), &CreateAccountRequest{})
+		assert.Error(t, err)
+		_, ok := manager.Account("")
+		assert.False(t, ok)
+	})
+
+	accounts := manager.Accounts()
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "Existing", accounts[0].ShortName)
+	assert.Equal(t, "Sandbox", accounts[1].ShortName)
+}
+
+func TestAccountManagerConcurrency(t *testing.T) {
+	var slowCalls atomic.Int32
+	slowStarted := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateAccountRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.ShortName == "Slow" {
+			if slowCalls.Add(1) == 1 {
+				close(slowStarted)
+			}
+			<-release
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{ShortName: req.ShortName, AccessToken: "token-" + req.ShortName}})
+	}))
+	defer server.Close()
+
+	manager := NewClient(WithBaseURL(server.URL)).NewAccountManager()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	results := make([]bool, 3)
+	accounts := make([]*Account, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			accounts[i], results[i], err = manager.CreateAccountIfAbsent(ctx, &CreateAccountRequest{ShortName: "Slow"})
+			assert.NoError(t, err)
+		}()
+	}
+	<-slowStarted
+
+	// Other names are served while the slow account is being created
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		manager.Add(nil)
+		manager.Add(&Account{ShortName: "Existing"})
+		_, created, err := manager.CreateAccountIfAbsent(ctx, &CreateAccountRequest{ShortName: "Fast"})
+		assert.NoError(t, err)
+		assert.True(t, created)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("manager blocked behind a call in flight")
+	}
+
+	// A waiter gives up when its context is done
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err := manager.CreateAccountIfAbsent(canceled, &CreateAccountRequest{ShortName: "Slow"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), slowCalls.Load())
+	createdCount := 0
+	for i, created := range results {
+		if created {
+			createdCount++
+		}
+		assert.Same(t, accounts[0], accounts[i])
+	}
+	assert.Equal(t, 1, createdCount)
+	assert.Len(t, manager.Accounts(), 3)
+}
+
+func TestClientBootstrap(t *testing.T) {
+	failPage := false
+	accountsCreated := 0
+	var pageTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createAccount":
+			var req CreateAccountRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			accountsCreated++
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{ShortName: req.ShortName, AccessToken: "token-" + req.ShortName}})
+		case "/createPage":
+			var req CreatePageRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			pageTokens = append(pageTokens, req.AccessToken)
+			if failPage {
+				json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "CONTENT_TOO_BIG"})
+				return
+			}
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Welcome-12-15", Title: req.Title}})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	firstPage := &CreatePageRequest{Title: "Welcome", Content: NewContentBuilder().AddParagraph("Hello").Build()}
+
+	t.Run("happy path", func(t *testing.T) {
+		account, page, err := client.Bootstrap(context.Background(), &CreateAccountRequest{ShortName: "Channel"}, firstPage)
+		require.NoError(t, err)
+		assert.Equal(t, "token-Channel", account.AccessToken)
+		assert.Equal(t, "Welcome-12-15", page.Path)
+		assert.Equal(t, []string{"token-Channel"}, pageTokens)
+		assert.Empty(t, firstPage.AccessToken)
+	})
+
+	t.Run("page failure", func(t *testing.T) {
+		failPage = true
+		defer func() { failPage = false }()
+
+		account, page, err := client.Bootstrap(context.Background(), &CreateAccountRequest{ShortName: "Other"}, firstPage)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `account "Other" created, but first page failed`)
+		require.NotNil(t, account)
+		assert.Equal(t, "token-Other", account.AccessToken)
+		assert.Nil(t, page)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindContentTooBig, apiErr.Kind)
+	})
+
+	t.Run("invalid page creates no account", func(t *testing.T) {
+		accountsCreated = 0
+		account, page, err := client.Bootstrap(context.Background(), &CreateAccountRequest{ShortName: "Third"}, &CreatePageRequest{Title: "Empty"})
+		assert.EqualError(t, err, "first page: content is required")
+		assert.Nil(t, account)
+		assert.Nil(t, page)
+		assert.Equal(t, 0, accountsCreated)
+
+		// The missing token is not reported, but other fields are still checked
+		_, _, err = client.Bootstrap(context.Background(), &CreateAccountRequest{ShortName: "Third"}, &CreatePageRequest{Title: strings.Repeat("x", 257), Content: []Node{{Tag: "p"}}})
+		assert.EqualError(t, err, "first page: title must be at most 256 characters")
+		assert.Equal(t, 0, accountsCreated)
+	})
+}
@@ -0,0 +1,307 @@
+package telegraph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests used by batch
+// helpers when a non-positive concurrency is given
+const DefaultBatchConcurrency = 4
+
+// ProgressFunc reports the progress of a batch operation. It is called once per
+// completed item with the number of completed items, the batch size, and the
+// item's result. Calls are serialized, so the callback does not need to be thread-safe.
+type ProgressFunc func(done, total int, page *Page, err error)
+
+// CreatePages creates multiple Telegraph pages concurrently
+//
+// At most concurrency requests are in flight at once, and all of them share the
+// client's rate limiter. The returned slices are in the same order as reqs; for a
+// failed request the page is nil and the corresponding error is set. When ctx is
+// canceled no new requests are started, and unstarted requests fail with ctx.Err().
+//
+// Example:
+//
+//	pages, errs := client.CreatePages(ctx, []*telegraph.CreatePageRequest{req1, req2}, 2)
+func (c *Client) CreatePages(ctx context.Context, reqs []*CreatePageRequest, concurrency int) ([]*Page, []error) {
+	return c.CreatePagesWithProgress(ctx, reqs, concurrency, nil)
+}
+
+// CreatePagesWithProgress is like CreatePages but invokes progress as each page completes
+//
+// Example:
+//
+//	pages, errs := client.CreatePagesWithProgress(ctx, reqs, 4, func(done, total int, page *telegraph.Page, err error) {
+//		fmt.Printf("%d/%d\n", done, total)
+//	})
+func (c *Client) CreatePagesWithProgress(ctx context.Context, reqs []*CreatePageRequest, concurrency int, progress ProgressFunc) ([]*Page, []error) {
+	pages := make([]*Page, len(reqs))
+	errs := make([]error, len(reqs))
+
+	var mu sync.Mutex
+	done := 0
+	report := func(page *Page, err error) {
+		if progress != nil {
+			mu.Lock()
+			done++
+			progress(done, len(reqs), page, err)
+			mu.Unlock()
+		}
+	}
+
+	scheduled := runBatch(ctx, len(reqs), concurrency, func(i int) {
+		pages[i], errs[i] = c.CreatePage(ctx, reqs[i])
+		report(pages[i], errs[i])
+	})
+
+	for i := scheduled; i < len(reqs); i++ {
+		errs[i] = ctx.Err()
+		report(nil, errs[i])
+	}
+
+	return pages, errs
+}
+
+// DedupeCreateRequests returns reqs without the requests that duplicate an
+// earlier one, so that a batch does not create the same page twice
+//
+// Two requests are duplicates when their titles are equal and their content has
+// the same ContentChecksum, so content that differs only in representation, such
+// as split text or JSON objects instead of Nodes, is still recognized. Author
+// fields are not compared. The first of each set of duplicates is kept, and the
+// order of the kept requests is preserved. Nil requests and requests whose
+// content cannot be checksummed are always kept, so that CreatePages reports
+// their errors.
+//
+// Example:
+//
+//	pages, errs := client.CreatePages(ctx, telegraph.DedupeCreateRequests(reqs), 4)
+func DedupeCreateRequests(reqs []*CreatePageRequest) []*CreatePageRequest {
+	type pageKey struct {
+		title    string
+		checksum string
+	}
+
+	seen := make(map[pageKey]bool, len(reqs))
+	unique := make([]*CreatePageRequest, 0, len(reqs))
+	for _, req := range reqs {
+		if req == nil {
+			unique = append(unique, req)
+			continue
+		}
+		checksum, err := ContentChecksum(req.Content)
+		if err != nil {
+			unique = append(unique, req)
+			continue
+		}
+
+		key := pageKey{title: req.Title, checksum: checksum}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, req)
+	}
+	return unique
+}
+
+// GetPages gets multiple Telegraph pages concurrently
+//
+// It follows the same concurrency, ordering and cancellation rules as CreatePages.
+//
+// Example:
+//
+//	pages, errs := client.GetPages(ctx, []*telegraph.GetPageRequest{
+//		{Path: "First-Article-12-15"},
+//		{Path: "Second-Article-12-15"},
+//	}, 2)
+func (c *Client) GetPages(ctx context.Context, reqs []*GetPageRequest, concurrency int) ([]*Page, []error) {
+	pages := make([]*Page, len(reqs))
+	errs := make([]error, len(reqs))
+
+	scheduled := runBatch(ctx, len(reqs), concurrency, func(i int) {
+		pages[i], errs[i] = c.GetPage(ctx, reqs[i])
+	})
+
+	for i := scheduled; i < len(reqs); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return pages, errs
+}
+
+// GetViewsBatch gets page views for multiple requests concurrently
+//
+// At most concurrency requests are in flight at once, and all of them share the
+// client's rate limiter. The returned slices are in the same order as reqs; for a
+// failed request the views are nil and the corresponding error is set. When ctx is
+// canceled no new requests are started, and unstarted requests fail with ctx.Err().
+//
+// Example:
+//
+//	views, errs := client.GetViewsBatch(ctx, []*telegraph.GetViewsRequest{
+//		{Path: "First-Article-12-15"},
+//		{Path: "Second-Article-12-15"},
+//	}, 2)
+func (c *Client) GetViewsBatch(ctx context.Context, reqs []*GetViewsRequest, concurrency int) ([]*PageViews, []error) {
+	views := make([]*PageViews, len(reqs))
+	errs := make([]error, len(reqs))
+
+	scheduled := runBatch(ctx, len(reqs), concurrency, func(i int) {
+		views[i], errs[i] = c.GetViews(ctx, reqs[i])
+	})
+
+	for i := scheduled; i < len(reqs); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return views, errs
+}
+
+// UpdateAllPagesAuthor sets the author name and URL of every page of the account
+//
+// Pages are listed first, then each page is fetched with its content and edited
+// with its title and content unchanged. Up to concurrency pages are updated at
+// once, sharing the client's rate limiter. The returned slices are in listing
+// order; for a page that could not be updated the page is nil and the error,
+// which names the page path, is set. If the pages cannot be listed, no page is
+// updated and the listing error is returned as the only error.
+//
+// Example:
+//
+//	pages, errs := client.UpdateAllPagesAuthor(ctx, token, "New Name", "https://example.com", 4)
+func (c *Client) UpdateAllPagesAuthor(ctx context.Context, accessToken, authorName, authorURL string, concurrency int) ([]*Page, []error) {
+	listed, err := c.GetAllPages(ctx, &GetPageListRequest{AccessToken: accessToken})
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list pages: %w", err)}
+	}
+
+	pages := make([]*Page, len(listed))
+	errs := make([]error, len(listed))
+
+	scheduled := runBatch(ctx, len(listed), concurrency, func(i int) {
+		path := listed[i].Path
+		page, err := c.getPage(ctx, &GetPageRequest{Path: path, ReturnContent: true})
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to update page %s: %w", path, err)
+			return
+		}
+
+		pages[i], err = c.EditPage(ctx, &EditPageRequest{
+			AccessToken: accessToken,
+			Path:        path,
+			Title:       page.Title,
+			RawTitle:    true,
+			AuthorName:  authorName,
+			AuthorURL:   authorURL,
+			Content:     page.Content,
+		})
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to update page %s: %w", path, err)
+		}
+	})
+
+	for i := scheduled; i < len(listed); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return pages, errs
+}
+
+// BatchPlan describes the work of a planned batch operation
+type BatchPlan struct {
+	// CreatePages is the number of pages to create with CreatePages
+	CreatePages int
+	// GetPages is the number of pages to fetch with GetPages
+	GetPages int
+	// GetViews is the number of view lookups made with GetViewsBatch
+	GetViews int
+	// ListPages is set when all pages of the account are listed with GetAllPages
+	ListPages bool
+	// PageCount is the number of pages of the account listed by GetAllPages
+	PageCount int
+	// ListLimit is the page list batch size used by GetAllPages (default: MaxPageListLimit)
+	ListLimit int
+}
+
+// EstimateCalls returns the number of API calls the plan makes when no request is retried
+//
+// Example:
+//
+//	calls := telegraph.EstimateCalls(telegraph.BatchPlan{CreatePages: len(reqs)})
+func EstimateCalls(plan BatchPlan) int {
+	calls := plan.CreatePages + plan.GetPages + plan.GetViews
+	if plan.ListPages {
+		limit := plan.ListLimit
+		if limit <= 0 {
+			limit = MaxPageListLimit
+		}
+		// Listing takes one call per batch, and a single call when there are no pages
+		calls += max(1, (plan.PageCount+limit-1)/limit)
+	}
+	return calls
+}
+
+// EstimateDuration returns the minimum time the plan takes under the client's rate limit
+//
+// Example:
+//
+//	eta := client.EstimateDuration(telegraph.BatchPlan{CreatePages: len(reqs)})
+func (c *Client) EstimateDuration(plan BatchPlan) time.Duration {
+	limit := c.rateLimiter.Limit()
+	if limit == rate.Inf || limit <= 0 {
+		return 0
+	}
+
+	// The limiter's burst is available immediately, and later calls are paced at its rate
+	throttled := EstimateCalls(plan) - c.rateLimiter.Burst()
+	if throttled <= 0 {
+		return 0
+	}
+	return time.Duration(float64(throttled) / float64(limit) * float64(time.Second))
+}
+
+// runBatch calls work for every index in [0, total) using at most concurrency
+// goroutines. Once ctx is done no new work is scheduled. runBatch always waits for
+// in-flight work to finish and returns the number of indexes that were scheduled,
+// which are always the first ones.
+func runBatch(ctx context.Context, total, concurrency int, work func(i int)) int {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	if concurrency > total {
+		concurrency = total
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(i)
+			}
+		}()
+	}
+
+	scheduled := 0
+dispatch:
+	for scheduled < total && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- scheduled:
+			scheduled++
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return scheduled
+}
@@ -0,0 +1,384 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCreatePages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreatePageRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		var resp APIResponse
+		if req.Title == "Fail" {
+			resp = APIResponse{Ok: false, Error: "PAGE_SAVE_FAILED"}
+		} else {
+			resp = APIResponse{Ok: true, Result: Page{Path: req.Title, Title: req.Title}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	titles := []string{"Page-0", "Page-1", "Fail", "Page-3", "Page-4"}
+	reqs := make([]*CreatePageRequest, len(titles))
+	for i, title := range titles {
+		reqs[i] = &CreatePageRequest{
+			AccessToken: "test-token",
+			Title:       title,
+			Content:     NewContentBuilder().AddParagraph(title).Build(),
+		}
+	}
+
+	t.Run("preserves order", func(t *testing.T) {
+		pages, errs := client.CreatePages(context.Background(), reqs, 3)
+		require.Len(t, pages, len(reqs))
+		require.Len(t, errs, len(reqs))
+
+		for i, title := range titles {
+			if title == "Fail" {
+				assert.Nil(t, pages[i])
+				assert.Error(t, errs[i])
+				continue
+			}
+			require.NoError(t, errs[i])
+			assert.Equal(t, title, pages[i].Title)
+		}
+	})
+
+	t.Run("reports progress", func(t *testing.T) {
+		var calls []int
+		failures := 0
+		_, _ = client.CreatePagesWithProgress(context.Background(), reqs, 3, func(done, total int, page *Page, err error) {
+			assert.Equal(t, len(reqs), total)
+			calls = append(calls, done)
+			if err != nil {
+				failures++
+				assert.Nil(t, page)
+			}
+		})
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, calls)
+		assert.Equal(t, 1, failures)
+	})
+}
+
+func TestDedupeCreateRequests(t *testing.T) {
+	article := &CreatePageRequest{
+		Title:   "Article",
+		Content: NewContentBuilder().AddParagraph("Hello, world").Build(),
+	}
+	// Same page with an uppercase tag and the text split across a string and a text Node
+	duplicate := &CreatePageRequest{
+		Title:      "Article",
+		AuthorName: "Someone else",
+		Content: []Node{{Tag: "P", Children: []interface{}{
+			"Hello, ",
+			Node{Content: "world"},
+		}}},
+	}
+	otherTitle := &CreatePageRequest{Title: "Article 2", Content: article.Content}
+	otherContent := &CreatePageRequest{
+		Title:   "Article",
+		Content: NewContentBuilder().AddParagraph("Hello, world!").Build(),
+	}
+	exact := &CreatePageRequest{Title: article.Title, Content: CloneContent(article.Content)}
+
+	reqs := []*CreatePageRequest{article, duplicate, otherTitle, nil, otherContent, exact, nil}
+	assert.Equal(t, []*CreatePageRequest{article, otherTitle, nil, otherContent, nil}, DedupeCreateRequests(reqs))
+
+	assert.Empty(t, DedupeCreateRequests(nil))
+
+	t.Run("nil requests fail without a panic", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Article-12-15", Title: "Article"}})
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+		valid := &CreatePageRequest{AccessToken: "test-token", Title: article.Title, Content: article.Content}
+		reqs := DedupeCreateRequests([]*CreatePageRequest{nil, valid, valid})
+		require.Len(t, reqs, 2)
+
+		pages, errs := client.CreatePages(context.Background(), reqs, 2)
+		assert.Nil(t, pages[0])
+		assert.EqualError(t, errs[0], "request is nil")
+		require.NoError(t, errs[1])
+		assert.Equal(t, "Article", pages[1].Title)
+	})
+}
+
+func TestClientGetViewsBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetViewsRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		var resp APIResponse
+		if req.Path == "Missing" {
+			resp = APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"}
+		} else {
+			resp = APIResponse{Ok: true, Result: PageViews{Views: len(req.Path)}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	reqs := []*GetViewsRequest{
+		{Path: "A"},
+		{Path: "Missing"},
+		{Path: "ABC"},
+		{Path: ""},
+		{Path: "ABCDE"},
+	}
+
+	views, errs := client.GetViewsBatch(context.Background(), reqs, 2)
+	require.Len(t, views, len(reqs))
+	require.Len(t, errs, len(reqs))
+
+	require.NoError(t, errs[0])
+	assert.Equal(t, 1, views[0].Views)
+
+	var apiErr *APIError
+	require.ErrorAs(t, errs[1], &apiErr)
+	assert.Equal(t, ErrorKindPageNotFound, apiErr.Kind)
+	assert.Nil(t, views[1])
+
+	require.NoError(t, errs[2])
+	assert.Equal(t, 3, views[2].Views)
+
+	assert.EqualError(t, errs[3], "path is required")
+	assert.Nil(t, views[3])
+
+	require.NoError(t, errs[4])
+	assert.Equal(t, 5, views[4].Views)
+}
+
+// assertNoGoroutineLeak fails the test if the number of goroutines does not
+// return to the baseline within a short grace period
+func assertNoGoroutineLeak(t *testing.T, baseline int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline, "goroutines leaked")
+}
+
+func TestBatchCancellation(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	var started int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&started, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+
+	client := NewClient(WithBaseURL(server.URL), WithRetryConfig(RetryConfig{}))
+
+	reqs := make([]*GetViewsRequest, 20)
+	for i := range reqs {
+		reqs[i] = &GetViewsRequest{Path: "Test-Article-12-15"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for atomic.LoadInt32(&started) < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	start := time.Now()
+	views, errs := client.GetViewsBatch(ctx, reqs, 2)
+	assert.Less(t, time.Since(start), time.Second, "batch should return promptly after cancellation")
+
+	require.Len(t, errs, len(reqs))
+	for i := range reqs {
+		assert.Nil(t, views[i])
+		assert.ErrorIs(t, errs[i], context.Canceled)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&started), int32(3), "no new work should be scheduled after cancellation")
+
+	close(release)
+	server.Close()
+	client.httpClient.CloseIdleConnections()
+	assertNoGoroutineLeak(t, baseline)
+}
+
+func TestClientGetPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Query().Get("path")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: path, Title: path}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	t.Run("preserves order", func(t *testing.T) {
+		pages, errs := client.GetPages(context.Background(), []*GetPageRequest{{Path: "A"}, {Path: "B"}, {Path: "C"}}, 2)
+		for i, path := range []string{"A", "B", "C"} {
+			require.NoError(t, errs[i])
+			assert.Equal(t, path, pages[i].Path)
+		}
+	})
+
+	t.Run("canceled context schedules nothing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		pages, errs := client.GetPages(ctx, []*GetPageRequest{{Path: "A"}, {Path: "B"}}, 2)
+		for i := range errs {
+			assert.Nil(t, pages[i])
+			assert.ErrorIs(t, errs[i], context.Canceled)
+		}
+	})
+}
+
+func TestClientUpdateAllPagesAuthor(t *testing.T) {
+	paths := []string{"First-12-15", "Second-12-15", "Third-12-15"}
+
+	var mu sync.Mutex
+	edits := map[string]EditPageRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch r.URL.Path {
+		case "/getPageList":
+			list := PageList{TotalCount: len(paths)}
+			for _, path := range paths {
+				list.Pages = append(list.Pages, Page{Path: path, Title: "Title " + path})
+			}
+			result = list
+		case "/getPage":
+			path := r.URL.Query().Get("path")
+			assert.Equal(t, "true", r.URL.Query().Get("return_content"))
+			result = Page{Path: path, Title: " Title " + path, Content: []Node{{Tag: "p", Children: []interface{}{"Body of " + path}}}}
+		case "/editPage":
+			var req EditPageRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.Path == "Third-12-15" {
+				json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "PAGE_ACCESS_DENIED"})
+				return
+			}
+			mu.Lock()
+			edits[req.Path] = req
+			mu.Unlock()
+			result = Page{Path: req.Path, URL: "https://telegra.ph/" + req.Path, Title: req.Title, AuthorName: req.AuthorName}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: result})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	pages, errs := client.UpdateAllPagesAuthor(context.Background(), "test-token", "New Name", "https://example.com/new", 2)
+	require.Len(t, pages, 3)
+	require.Len(t, errs, 3)
+
+	for i, path := range paths[:2] {
+		require.NoError(t, errs[i])
+		assert.Equal(t, path, pages[i].Path)
+		assert.Equal(t, "New Name", pages[i].AuthorName)
+
+		edit := edits[path]
+		assert.Equal(t, "test-token", edit.AccessToken)
+		assert.Equal(t, " Title "+path, edit.Title)
+		assert.Equal(t, "New Name", edit.AuthorName)
+		assert.Equal(t, "https://example.com/new", edit.AuthorURL)
+		require.Len(t, edit.Content, 1)
+		assert.Equal(t, []interface{}{"Body of " + path}, edit.Content[0].Children)
+	}
+
+	assert.Nil(t, pages[2])
+	require.Error(t, errs[2])
+	assert.Contains(t, errs[2].Error(), "failed to update page Third-12-15")
+}
+
+func TestEstimateCalls(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var result interface{}
+		switch r.URL.Path {
+		case "/createPage", "/getPage":
+			result = Page{Path: "Test-Article-12-15"}
+		case "/getViews":
+			result = PageViews{Views: 1}
+		case "/getPageList":
+			var req GetPageListRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			pages := make([]Page, max(0, min(req.Limit, 5-req.Offset)))
+			result = PageList{TotalCount: 5, Pages: pages}
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: result})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(1000))
+	ctx := context.Background()
+
+	plan := BatchPlan{CreatePages: 3, GetPages: 2, GetViews: 4, ListPages: true, PageCount: 5, ListLimit: 2}
+
+	createReqs := make([]*CreatePageRequest, plan.CreatePages)
+	for i := range createReqs {
+		createReqs[i] = &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: NewContentBuilder().AddParagraph("Hello").Build()}
+	}
+	getReqs := make([]*GetPageRequest, plan.GetPages)
+	for i := range getReqs {
+		getReqs[i] = &GetPageRequest{Path: "Test-Article-12-15"}
+	}
+	viewReqs := make([]*GetViewsRequest, plan.GetViews)
+	for i := range viewReqs {
+		viewReqs[i] = &GetViewsRequest{Path: "Test-Article-12-15"}
+	}
+
+	client.CreatePages(ctx, createReqs, 2)
+	client.GetPages(ctx, getReqs, 2)
+	client.GetViewsBatch(ctx, viewReqs, 2)
+	pages, err := client.GetAllPages(ctx, &GetPageListRequest{AccessToken: "test-token", Limit: plan.ListLimit})
+	require.NoError(t, err)
+	require.Len(t, pages, plan.PageCount)
+
+	assert.Equal(t, 12, EstimateCalls(plan))
+	assert.Equal(t, int(requests.Load()), EstimateCalls(plan))
+
+	t.Run("empty account", func(t *testing.T) {
+		assert.Equal(t, 1, EstimateCalls(BatchPlan{ListPages: true}))
+		assert.Equal(t, 0, EstimateCalls(BatchPlan{}))
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		client := NewClient(WithRateLimit(10))
+		assert.Zero(t, client.EstimateDuration(BatchPlan{CreatePages: 10}))
+		assert.Equal(t, 2*time.Second, client.EstimateDuration(BatchPlan{CreatePages: 30}))
+	})
+}
@@ -0,0 +1,51 @@
+package telegraph
+
+// BlockType is the kind of a Block
+type BlockType string
+
+const (
+	// BlockParagraph is a paragraph of text, rendered as p
+	BlockParagraph BlockType = "paragraph"
+	// BlockHeading is a section heading, rendered as h3
+	BlockHeading BlockType = "heading"
+	// BlockCode is preformatted code, rendered as pre
+	BlockCode BlockType = "code"
+	// BlockQuote is a quotation, rendered as blockquote
+	BlockQuote BlockType = "quote"
+)
+
+// Block is a typed block of plain text, as found in chat and messaging exports
+type Block struct {
+	Type BlockType `json:"type"`
+	Text string    `json:"text"`
+}
+
+// ContentFromBlocks converts a flat list of blocks into Telegraph content
+//
+// Each block becomes one node, in order. Blocks of an unknown type are treated
+// as paragraphs, and blocks without text are skipped.
+//
+// Example:
+//
+//	var blocks []telegraph.Block
+//	err := json.Unmarshal(export, &blocks)
+//	content := telegraph.ContentFromBlocks(blocks)
+func ContentFromBlocks(blocks []Block) []Node {
+	cb := NewContentBuilder()
+	for _, block := range blocks {
+		if block.Text == "" {
+			continue
+		}
+		switch block.Type {
+		case BlockHeading:
+			cb.AddHeading(block.Text, 3)
+		case BlockCode:
+			cb.AddCodeBlock(block.Text)
+		case BlockQuote:
+			cb.AddBlockquote(block.Text)
+		default:
+			cb.AddParagraph(block.Text)
+		}
+	}
+	return cb.Build()
+}
@@ -0,0 +1,38 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFromBlocks(t *testing.T) {
+	var blocks []Block
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type": "heading", "text": "Release notes"},
+		{"type": "paragraph", "text": "Version 2 is out."},
+		{"type": "code", "text": "go get example.com/tool@v2"},
+		{"type": "quote", "text": "Finally!"},
+		{"type": "sticker", "text": "Unknown types become paragraphs"},
+		{"type": "paragraph", "text": ""}
+	]`), &blocks))
+
+	content := ContentFromBlocks(blocks)
+	require.Len(t, content, 5)
+
+	for i, want := range []struct{ tag, text string }{
+		{"h3", "Release notes"},
+		{"p", "Version 2 is out."},
+		{"pre", "go get example.com/tool@v2"},
+		{"blockquote", "Finally!"},
+		{"p", "Unknown types become paragraphs"},
+	} {
+		assert.Equal(t, want.tag, content[i].Tag)
+		assert.Equal(t, want.text, PlainText(content[i:i+1]))
+	}
+	assert.NoError(t, ValidateContent(content))
+
+	assert.Empty(t, ContentFromBlocks(nil))
+}
@@ -0,0 +1,239 @@
+package telegraph
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultPageCacheSize is the maximum number of responses kept by WithPageCache
+const DefaultPageCacheSize = 1000
+
+// CacheResult describes how a call was served by the client's page cache
+type CacheResult int
+
+const (
+	// CacheNone is used for calls that did not consult the cache
+	CacheNone CacheResult = iota
+	// CacheHit is used for calls answered from the cache after the API reported no changes
+	CacheHit
+	// CacheMiss is used for cacheable calls whose response was fetched in full
+	CacheMiss
+)
+
+// String returns a lowercase name for the result
+func (r CacheResult) String() string {
+	switch r {
+	case CacheHit:
+		return "hit"
+	case CacheMiss:
+		return "miss"
+	default:
+		return "none"
+	}
+}
+
+// WithPageCache enables an in-memory cache of GetPage responses keyed by page path.
+//
+// Cached pages are revalidated with If-None-Match using the ETag of the last
+// response, and returned as-is when the API answers 304 Not Modified. The
+// Telegraph API itself sends no ETags, so set a freshness period with
+// WithPageCacheTTL to serve pages from the cache without a call; without one,
+// responses without an ETag are not cached, and replace any cached copy.
+// Hits and misses of calls are reported to the metrics hook through
+// RequestMetrics.Cache. At most DefaultPageCacheSize responses are kept,
+// evicting the least recently used; see WithPageCacheSize.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithPageCache())
+func WithPageCache() ClientOption {
+	return WithPageCacheSize(DefaultPageCacheSize)
+}
+
+// WithPageCacheSize enables the page cache like WithPageCache, keeping at most
+// maxEntries responses. A non-positive maxEntries uses DefaultPageCacheSize.
+func WithPageCacheSize(maxEntries int) ClientOption {
+	return func(c *Client) {
+		if maxEntries <= 0 {
+			maxEntries = DefaultPageCacheSize
+		}
+		c.pageCache = &pageCache{
+			maxEntries: maxEntries,
+			entries:    make(map[pageCacheKey]*list.Element),
+			order:      list.New(),
+		}
+	}
+}
+
+// WithPageCacheTTL enables the page cache like WithPageCache, and serves
+// cached pages younger than ttl without calling the API. Responses are cached
+// for ttl whether or not they have an ETag; once stale, a page with an ETag is
+// revalidated and one without is fetched again. Pages served without a call
+// are not reported to the metrics hook.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithPageCacheTTL(time.Minute))
+func WithPageCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.pageCache == nil {
+			WithPageCache()(c)
+		}
+		c.pageCacheTTL = ttl
+	}
+}
+
+// pageCacheLookupKey is the context key marking getPage calls that consulted the cache
+type pageCacheLookupKey struct{}
+
+// isPageCacheLookup reports whether ctx belongs to a getPage call that consulted the cache
+func isPageCacheLookup(ctx context.Context) bool {
+	lookup, _ := ctx.Value(pageCacheLookupKey{}).(bool)
+	return lookup
+}
+
+// pageCacheKey identifies a cached getPage response
+type pageCacheKey struct {
+	path          string
+	returnContent bool
+}
+
+// pageCacheEntry is a cached page with the ETag it was served with, if any
+type pageCacheEntry struct {
+	etag    string
+	page    Page
+	fetched time.Time
+}
+
+// pageCacheItem is the value of an element of pageCache.order
+type pageCacheItem struct {
+	key   pageCacheKey
+	entry pageCacheEntry
+}
+
+// pageCache stores the last getPage response of each path, evicting the least
+// recently used responses beyond maxEntries
+type pageCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[pageCacheKey]*list.Element
+	// order holds the cached items, most recently used first
+	order *list.List
+}
+
+func (pc *pageCache) get(key pageCacheKey) (pageCacheEntry, bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	elem, ok := pc.entries[key]
+	if !ok {
+		return pageCacheEntry{}, false
+	}
+	pc.order.MoveToFront(elem)
+	return elem.Value.(*pageCacheItem).entry, true
+}
+
+func (pc *pageCache) put(key pageCacheKey, entry pageCacheEntry) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if elem, ok := pc.entries[key]; ok {
+		elem.Value.(*pageCacheItem).entry = entry
+		pc.order.MoveToFront(elem)
+		return
+	}
+
+	pc.entries[key] = pc.order.PushFront(&pageCacheItem{key: key, entry: entry})
+	for pc.order.Len() > pc.maxEntries {
+		oldest := pc.order.Back()
+		pc.order.Remove(oldest)
+		delete(pc.entries, oldest.Value.(*pageCacheItem).key)
+	}
+}
+
+// remove drops the cached response for key
+func (pc *pageCache) remove(key pageCacheKey) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if elem, ok := pc.entries[key]; ok {
+		pc.order.Remove(elem)
+		delete(pc.entries, key)
+	}
+}
+
+// invalidate drops every cached response for path. It is a no-op on a nil cache.
+func (pc *pageCache) invalidate(path string) {
+	if pc == nil {
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	for _, key := range []pageCacheKey{{path: path}, {path: path, returnContent: true}} {
+		if elem, ok := pc.entries[key]; ok {
+			pc.order.Remove(elem)
+			delete(pc.entries, key)
+		}
+	}
+}
+
+// getCachedPage fetches a page, serving the cached copy while it is fresh and
+// revalidating it afterwards if it has an ETag
+func (c *Client) getCachedPage(ctx context.Context, req *GetPageRequest, endpoint string) (*Page, error) {
+	key := pageCacheKey{path: req.Path, returnContent: req.ReturnContent}
+	cached, ok := c.pageCache.get(key)
+	if ok && c.pageCacheTTL > 0 && c.now().Sub(cached.fetched) < c.pageCacheTTL {
+		return cached.clone(), nil
+	}
+
+	var header http.Header
+	if ok && cached.etag != "" {
+		header = http.Header{"If-None-Match": {cached.etag}}
+	}
+
+	ctx = context.WithValue(ctx, pageCacheLookupKey{}, true)
+	resp, err := c.doRequestWithHeader(ctx, "GET", endpoint, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if header != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		cached.fetched = c.now()
+		c.pageCache.put(key, cached)
+		return cached.clone(), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+
+	var page Page
+	if err := c.parseResponse(resp, &page); err != nil {
+		return nil, err
+	}
+
+	// A response without an ETag cannot be revalidated, so it replaces the
+	// cached copy only if it can be served while fresh
+	if etag == "" && c.pageCacheTTL <= 0 {
+		c.pageCache.remove(key)
+		return &page, nil
+	}
+	entry := pageCacheEntry{etag: etag, page: page, fetched: c.now()}
+	entry.page = *entry.clone()
+	c.pageCache.put(key, entry)
+
+	return &page, nil
+}
+
+// clone returns a copy of the cached page that shares no content with the cache
+func (e pageCacheEntry) clone() *Page {
+	page := e.page
+	if page.Content != nil {
+		page.Content = CloneContent(e.page.Content)
+	}
+	return &page
+}
@@ -0,0 +1,186 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPageCache(t *testing.T) {
+	const etag = `"v1"`
+	var requests, fullResponses int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		fullResponses++
+		w.Header().Set("ETag", etag)
+		json.NewEncoder(w).Encode(APIResponse{
+			Ok: true,
+			Result: Page{
+				Path:    "Test-Article-12-15",
+				Title:   "Test Article",
+				Content: []Node{{Tag: "p", Children: []interface{}{"Hello"}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	var results []CacheResult
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithPageCache(),
+		WithMetricsHook(func(ctx context.Context, m RequestMetrics) {
+			results = append(results, m.Cache)
+		}),
+	)
+
+	req := &GetPageRequest{Path: "Test-Article-12-15", ReturnContent: true}
+	first, err := client.GetPage(context.Background(), req)
+	require.NoError(t, err)
+
+	second, err := client.GetPage(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, fullResponses)
+	assert.Equal(t, first, second)
+	assert.Equal(t, []CacheResult{CacheMiss, CacheHit}, results)
+
+	// Pages returned from the cache do not share content with it
+	second.Content[0].Children[0] = "changed"
+	third, err := client.GetPage(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", third.Content[0].Children[0])
+
+	t.Run("disabled", func(t *testing.T) {
+		requests, fullResponses = 0, 0
+		client := NewClient(WithBaseURL(server.URL))
+		for i := 0; i < 2; i++ {
+			_, err := client.GetPage(context.Background(), req)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, fullResponses)
+	})
+
+	t.Run("calls that bypass the cache are not misses", func(t *testing.T) {
+		results = nil
+		_, err := client.CanEditPage(context.Background(), "test-token", "Test-Article-12-15")
+		require.NoError(t, err)
+		_, _, err = client.EditableContent(context.Background(), "test-token", "Test-Article-12-15")
+		require.NoError(t, err)
+		assert.Equal(t, []CacheResult{CacheNone, CacheNone}, results)
+	})
+
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL), WithPageCacheSize(2))
+		get := func(path string) {
+			_, err := client.GetPage(context.Background(), &GetPageRequest{Path: path})
+			require.NoError(t, err)
+		}
+		cached := func(path string) bool {
+			_, ok := client.pageCache.get(pageCacheKey{path: path})
+			return ok
+		}
+
+		get("First-12-15")
+		get("Second-12-15")
+		get("First-12-15")
+		get("Third-12-15")
+
+		assert.True(t, cached("First-12-15"))
+		assert.False(t, cached("Second-12-15"))
+		assert.True(t, cached("Third-12-15"))
+		assert.Equal(t, 2, client.pageCache.order.Len())
+	})
+
+	t.Run("invalidated by EditPage", func(t *testing.T) {
+		_, err := client.EditPage(context.Background(), &EditPageRequest{
+			AccessToken: "test-token",
+			Path:        "Test-Article-12-15",
+			Title:       "Test Article",
+			Content:     []Node{{Tag: "p", Children: []interface{}{"Edited"}}},
+		})
+		require.NoError(t, err)
+
+		_, ok := client.pageCache.get(pageCacheKey{path: "Test-Article-12-15", returnContent: true})
+		assert.False(t, ok)
+	})
+}
+
+func TestClientPageCacheWithoutETag(t *testing.T) {
+	var requests int
+	sendETag := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if sendETag {
+			w.Header().Set("ETag", `"v1"`)
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15", Title: "Version " + string(rune('0'+requests))}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	req := &GetPageRequest{Path: "Test-12-15"}
+	key := pageCacheKey{path: "Test-12-15"}
+
+	t.Run("response without ETag replaces the cached copy", func(t *testing.T) {
+		requests, sendETag = 0, true
+		client := NewClient(WithBaseURL(server.URL), WithPageCache())
+
+		_, err := client.GetPage(ctx, req)
+		require.NoError(t, err)
+		_, ok := client.pageCache.get(key)
+		require.True(t, ok)
+
+		sendETag = false
+		page, err := client.GetPage(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, "Version 2", page.Title)
+		_, ok = client.pageCache.get(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("fresh pages are served without a call", func(t *testing.T) {
+		requests, sendETag = 0, false
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		var results []CacheResult
+		client := NewClient(
+			WithBaseURL(server.URL),
+			WithPageCacheTTL(time.Minute),
+			WithClock(func() time.Time { return now }),
+			WithMetricsHook(func(ctx context.Context, m RequestMetrics) {
+				results = append(results, m.Cache)
+			}),
+		)
+
+		first, err := client.GetPage(ctx, req)
+		require.NoError(t, err)
+		now = now.Add(30 * time.Second)
+		second, err := client.GetPage(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, 1, requests)
+		assert.Equal(t, first, second)
+
+		// Once stale, the page is fetched again and replaces the cached copy
+		now = now.Add(time.Minute)
+		third, err := client.GetPage(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, 2, requests)
+		assert.Equal(t, "Version 2", third.Title)
+
+		cached, ok := client.pageCache.get(key)
+		require.True(t, ok)
+		assert.Equal(t, "Version 2", cached.page.Title)
+		assert.Equal(t, []CacheResult{CacheMiss, CacheMiss}, results)
+	})
+}
@@ -34,13 +34,20 @@ package telegraph
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/html"
@@ -53,15 +60,88 @@ type Client struct {
 	baseURL     string
 	rateLimiter *rate.Limiter
 	retryConfig RetryConfig
-	mu          sync.RWMutex
+	// priorityLimiter limits priority calls that find rateLimiter exhausted
+	priorityLimiter *rate.Limiter
+	// retryBudget caps the retries made across all calls, if set
+	retryBudget *rate.Limiter
+	tagMappings map[string]string
+	// stateMu guards accessToken and defaultAuthor, which may change while
+	// calls are in flight. It is never held across network I/O or sleeps.
+	stateMu sync.RWMutex
+	// accessToken is the token stored with SetAccessToken or LoadState
+	accessToken string
+	// defaultAuthor is applied to page requests without an explicit author
+	defaultAuthor *Account
+	metricsHook   MetricsHook
+	// requestHook is called before every request attempt, if set
+	requestHook RequestHook
+	// faultInjector fails request attempts for testing, if set
+	faultInjector FaultInjector
+	// urlRewriter rewrites the URL of every request attempt, if set
+	urlRewriter URLRewriter
+	// endpointTimeouts holds per-call deadlines keyed by API method name
+	endpointTimeouts map[string]time.Duration
+	uploadURL        string
+	// fallbackBaseURL is tried once after connection failures against baseURL, if set
+	fallbackBaseURL string
+	// noRedirects disables following redirects from the API
+	noRedirects bool
+	// minTLSVersion is the minimum TLS version of the default transport, if set
+	minTLSVersion uint16
+	// responseHeaderTimeout is the ResponseHeaderTimeout of the default transport, if set
+	responseHeaderTimeout time.Duration
+	// returnContent overrides the ReturnContent field of page requests, if set
+	returnContent *bool
+	// compressRequests enables gzip compression of request bodies
+	compressRequests bool
+	// compressionRejected is set once the server rejects a compressed request
+	compressionRejected atomic.Bool
+	// defaultPageListLimit is sent as the limit of getPageList calls without one, if set
+	defaultPageListLimit int
+	// stats counts the calls made by the client
+	stats clientStats
+	// defaultTimeout limits calls to endpoints without a timeout, if set
+	defaultTimeout time.Duration
+	// clock returns the current time for date-derived helpers, if set
+	clock func() time.Time
+	// dryValidation enables DryValidateContent, which creates real pages
+	dryValidation bool
+	// validatePages enables Page.Validate on pages returned by createPage and editPage
+	validatePages bool
+	// pageCacheTTL is how long cached pages are served without a call, if set
+	pageCacheTTL time.Duration
+	// pageCache holds getPage responses for conditional requests, if enabled
+	pageCache   *pageCache
+	contentType string
+	accept      string
+	// emptyErrorMessage describes ok:false responses without an error, if set
+	emptyErrorMessage string
 }
 
 // RetryConfig defines retry behavior for failed requests
+//
+// By default only read endpoints (getPage, getPageList, getViews, getAccountInfo)
+// are retried on network errors and 5xx responses, since retrying a write may
+// create duplicates. Writes are retried on 429 only, unless RetryNonIdempotent is set.
 type RetryConfig struct {
 	MaxRetries   int
 	InitialDelay time.Duration
 	MaxDelay     time.Duration
 	Multiplier   float64
+	// RetryNonIdempotent enables retries of write endpoints on network errors and 5xx responses
+	RetryNonIdempotent bool
+	// MaxFloodWait enables retries of calls rejected with FLOOD_WAIT_X errors,
+	// waiting the X seconds asked for, if X seconds is at most MaxFloodWait.
+	// Zero disables flood-wait retries.
+	MaxFloodWait time.Duration
+	// Jitter is the upper bound of a random delay added to flood waits, so that
+	// workers rejected together do not all retry at the same moment
+	Jitter time.Duration
+	// RetryableKinds lists the kinds of ok:false errors that are retried with
+	// the usual backoff, for every endpoint. Only list kinds for which the call
+	// is known not to have been carried out, such as transient failures.
+	// Flood waits are governed by MaxFloodWait, so ErrorKindFloodWait is ignored.
+	RetryableKinds []ErrorKind
 }
 
 // DefaultRetryConfig provides sensible defaults for retry behavior
@@ -72,16 +152,111 @@ var DefaultRetryConfig = RetryConfig{
 	Multiplier:   2.0,
 }
 
+// RequestMetrics describes a single API call made by the client
+type RequestMetrics struct {
+	// Endpoint is the API method name, e.g. "createPage"
+	Endpoint string
+	// RateLimitWait is the time spent blocked by the client's rate limiter
+	RateLimitWait time.Duration
+	// Duration is the total time of the call, including rate limiting and retries
+	Duration time.Duration
+	// StatusCode is the HTTP status code of the final response, or 0 if none was received
+	StatusCode int
+	// Retries is the number of attempts made after the first one
+	Retries int
+	// Err is the transport-level error of the call, if any. API errors are
+	// reported by the calling method after the response is parsed.
+	Err error
+	// Cache reports whether the call was served from the page cache
+	Cache CacheResult
+}
+
+// MetricsHook is called after every API call with metrics about the call
+type MetricsHook func(ctx context.Context, metrics RequestMetrics)
+
+// RequestHook is called before every attempt of an API call is sent, with the
+// attempt number, starting at 1 for the first attempt
+type RequestHook func(req *http.Request, attempt int)
+
+// attemptKey is the context key holding the attempt number of a request
+type attemptKey struct{}
+
+// AttemptFromContext returns the attempt number of the API request attempt whose
+// context is ctx, starting at 1 for the first attempt, or 0 if ctx is not the
+// context of a request attempt. It lets custom transports of the HTTP client
+// tell retries apart.
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// URLRewriter returns the URL to use for an API request, given its HTTP method and computed URL
+type URLRewriter func(method, url string) string
+
 // ClientOption represents a configuration option for the Telegraph client
 type ClientOption func(*Client)
 
 // WithHTTPClient sets a custom HTTP client
+//
+// The client is used as-is, so WithMinTLSVersion has no effect on it; set
+// TLSClientConfig.MinVersion on its transport instead.
 func WithHTTPClient(client *http.Client) ClientOption {
 	return func(c *Client) {
 		c.httpClient = client
 	}
 }
 
+// WithFallbackBaseURL sets a Telegraph-compatible mirror to try once when a
+// call fails to reach the base URL, after its retries are exhausted
+//
+// Only connection-level failures fall back; API errors and HTTP error
+// responses from the base URL are returned as usual. Calls that are not
+// retried as idempotent (see RetryConfig) only fall back when no connection
+// could be established, so a write is never sent to both hosts.
+func WithFallbackBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.fallbackBaseURL = baseURL
+	}
+}
+
+// WithFollowRedirects sets whether redirects from the API are followed (default: true)
+//
+// Requests carry their body with them, so 307 and 308 redirects repeat the
+// request with the same method and body. As with any HTTP client, a POST
+// redirected with 301, 302 or 303 becomes a GET without a body. When
+// redirects are not followed, a redirect response is returned as an APIError
+// with the redirect status code.
+func WithFollowRedirects(follow bool) ClientOption {
+	return func(c *Client) {
+		c.noRedirects = !follow
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version of the default transport,
+// e.g. tls.VersionTLS13. The default is tls.VersionTLS12.
+//
+// It only applies to the client's own transport: an HTTP client set with
+// WithHTTPClient is not modified.
+func WithMinTLSVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		c.minTLSVersion = version
+	}
+}
+
+// WithResponseHeaderTimeout sets how long the default transport waits for the
+// response headers of a request after sending it, so that a server that
+// accepts connections but never responds fails fast instead of running into the
+// overall timeout. It does not limit reading the response body.
+//
+// It only applies to the client's own transport: an HTTP client set with
+// WithHTTPClient is not modified, so set ResponseHeaderTimeout on its
+// transport instead.
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.responseHeaderTimeout = d
+	}
+}
+
 // WithBaseURL sets a custom base URL for the API
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
@@ -96,56 +271,513 @@ func WithRateLimit(rps rate.Limit) ClientOption {
 	}
 }
 
+// WithSharedRateLimiter makes the client wait on limiter instead of its own rate limiter
+//
+// Clients given the same limiter share its rate limit, so that several clients
+// in one process, such as one per account, together stay within the limits of
+// the Telegraph API. The limiter is used as-is and may be adjusted at runtime
+// with SetLimit and SetBurst. Coordinating rate limits across processes is out
+// of scope; each process needs its own share of the limit. A nil limiter leaves
+// the client's rate limiter unchanged.
+//
+// Example:
+//
+//	limiter := rate.NewLimiter(10, 10)
+//	alice := telegraph.NewClient(telegraph.WithSharedRateLimiter(limiter))
+//	bob := telegraph.NewClient(telegraph.WithSharedRateLimiter(limiter))
+func WithSharedRateLimiter(limiter *rate.Limiter) ClientOption {
+	return func(c *Client) {
+		if limiter != nil {
+			c.rateLimiter = limiter
+		}
+	}
+}
+
+// MinRetryDelay is the smallest delay between retries accepted by WithRetryConfig
+const MinRetryDelay = time.Millisecond
+
+// Validate reports the first setting of the RetryConfig that WithRetryConfig would clamp
+func (rc RetryConfig) Validate() error {
+	switch {
+	case rc.MaxRetries < 0:
+		return fmt.Errorf("max retries must be non-negative, got %d", rc.MaxRetries)
+	case rc.InitialDelay < MinRetryDelay:
+		return fmt.Errorf("initial delay must be at least %s, got %s", MinRetryDelay, rc.InitialDelay)
+	case rc.MaxDelay < rc.InitialDelay:
+		return fmt.Errorf("max delay must be at least the initial delay of %s, got %s", rc.InitialDelay, rc.MaxDelay)
+	case rc.Multiplier < 1:
+		return fmt.Errorf("multiplier must be at least 1, got %g", rc.Multiplier)
+	case rc.MaxFloodWait < 0:
+		return fmt.Errorf("max flood wait must be non-negative, got %s", rc.MaxFloodWait)
+	case rc.Jitter < 0:
+		return fmt.Errorf("jitter must be non-negative, got %s", rc.Jitter)
+	}
+	return nil
+}
+
+// clamped returns a copy of the RetryConfig with nonsensical settings replaced
+// by the nearest sensible value
+func (rc RetryConfig) clamped() RetryConfig {
+	if rc.MaxRetries < 0 {
+		rc.MaxRetries = 0
+	}
+	if rc.InitialDelay < MinRetryDelay {
+		rc.InitialDelay = MinRetryDelay
+	}
+	if rc.MaxDelay < rc.InitialDelay {
+		rc.MaxDelay = rc.InitialDelay
+	}
+	if rc.Multiplier < 1 {
+		rc.Multiplier = 1
+	}
+	if rc.MaxFloodWait < 0 {
+		rc.MaxFloodWait = 0
+	}
+	if rc.Jitter < 0 {
+		rc.Jitter = 0
+	}
+	return rc
+}
+
 // WithRetryConfig sets the retry configuration
+//
+// Nonsensical settings are clamped so that retries always back off: a negative
+// MaxRetries disables retries, InitialDelay is at least MinRetryDelay, MaxDelay
+// is at least InitialDelay, Multiplier is at least 1, and negative MaxFloodWait
+// and Jitter are treated as zero. Use
+// RetryConfig.Validate to reject such settings instead.
+//
+// When a call's context has a deadline, a retry whose delay would not end
+// before the deadline is not attempted: the call fails at once with an error
+// wrapping context.DeadlineExceeded and the last attempt's error.
 func WithRetryConfig(config RetryConfig) ClientOption {
 	return func(c *Client) {
-		c.retryConfig = config
+		c.retryConfig = config.clamped()
+	}
+}
+
+// ErrRateLimitWait is returned when a call gives up waiting for the client's rate
+// limiter, because its context was canceled or its deadline would pass first. It
+// distinguishes being throttled from the HTTP request itself timing out.
+var ErrRateLimitWait = errors.New("rate limit wait failed")
+
+// ErrNilRequest is returned by calls given a nil request
+var ErrNilRequest = errors.New("request is nil")
+
+// ErrRetryBudgetExhausted is returned when a failed request is not retried
+// because the client's retry budget has been used up
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// WithRetryBudget caps the retries made by all calls of the client to ratePerSec
+// on average, allowing bursts of up to burst retries. Once the budget is exhausted,
+// failed requests are not retried and return an error wrapping ErrRetryBudgetExhausted.
+// First attempts are never limited by the budget.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithRetryBudget(1, 10))
+func WithRetryBudget(ratePerSec float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = rate.NewLimiter(rate.Limit(ratePerSec), burst)
+	}
+}
+
+// WithURLRewriter sets a function that can inspect and rewrite the URL of every
+// API request, e.g. to route specific endpoints through a proxy. It is invoked
+// before each attempt, including retries.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithURLRewriter(func(method, url string) string {
+//		return strings.Replace(url, "https://api.telegra.ph", "https://proxy.example.com", 1)
+//	}))
+func WithURLRewriter(rewriter URLRewriter) ClientOption {
+	return func(c *Client) {
+		c.urlRewriter = rewriter
+	}
+}
+
+// WithReturnContent sets whether createPage, editPage and getPage return the page content
+//
+// ReturnContent is a plain bool in requests, so a request cannot tell "false" from
+// "not set". WithReturnContent resolves this at the client level: when it is
+// used, its value is sent with every page request and takes precedence over the
+// requests' ReturnContent fields, including WithPageReturnContent. Without it,
+// the ReturnContent field of each request is sent as-is.
+func WithReturnContent(returnContent bool) ClientOption {
+	return func(c *Client) {
+		c.returnContent = &returnContent
+	}
+}
+
+// resolveReturnContent returns the ReturnContent value to send for a request
+// whose ReturnContent field is requested
+func (c *Client) resolveReturnContent(requested bool) bool {
+	if c.returnContent != nil {
+		return *c.returnContent
+	}
+	return requested
+}
+
+// WithPageValidation makes CreatePage, CreatePageRaw and EditPage validate the
+// returned page with Page.Validate, failing with an error wrapping ErrMalformedPage
+// when a misbehaving server or proxy returns a page without a path or URL.
+func WithPageValidation() ClientOption {
+	return func(c *Client) {
+		c.validatePages = true
+	}
+}
+
+// WithTagMapping registers custom HTML tag mappings used by ConvertHTMLToPage.
+// Mappings take precedence over the built-in ones, which remain as a fallback.
+// Mappings to tags the Telegraph API does not support are ignored, so that
+// the built-in mapping applies to those tags instead.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithTagMapping(map[string]string{"mark": "strong"}))
+func WithTagMapping(mappings map[string]string) ClientOption {
+	return func(c *Client) {
+		if c.tagMappings == nil {
+			c.tagMappings = make(map[string]string, len(mappings))
+		}
+		for from, to := range mappings {
+			to = strings.ToLower(to)
+			if !supportedTags[to] {
+				continue
+			}
+			c.tagMappings[strings.ToLower(from)] = to
+		}
+	}
+}
+
+// WithRequestHook sets a hook called before every request attempt is sent,
+// including retries, so that logging can annotate them. It is called once per
+// attempt: when a compressed request is rejected and resent uncompressed (see
+// WithRequestCompression), the resend is part of the same attempt and is not
+// reported again. The hook is called without holding any client lock, so it
+// may call the client's methods, such as AccessToken.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithRequestHook(func(req *http.Request, attempt int) {
+//		log.Printf("%s %s (attempt %d)", req.Method, req.URL.Path, attempt)
+//	}))
+func WithRequestHook(hook RequestHook) ClientOption {
+	return func(c *Client) {
+		c.requestHook = hook
+	}
+}
+
+// WithMetricsHook sets a hook that receives metrics for every API call,
+// such as the time spent waiting on the rate limiter
+func WithMetricsHook(hook MetricsHook) ClientOption {
+	return func(c *Client) {
+		c.metricsHook = hook
+	}
+}
+
+// WithEndpointTimeout sets the timeout of every call to the given API method,
+// e.g. "createPage"
+//
+// Timeouts apply in this order of precedence: the deadline of the call's
+// context, the endpoint's timeout, the client's default timeout (see
+// WithDefaultTimeout), and finally the HTTP client's Timeout. The first three
+// limit the whole call, retries included, while the HTTP client's Timeout
+// limits each attempt and only applies if none of the others is set.
+//
+// Example:
+//
+//	client := telegraph.NewClient(
+//		telegraph.WithEndpointTimeout("createPage", 60*time.Second),
+//		telegraph.WithEndpointTimeout("getViews", 5*time.Second),
+//	)
+func WithEndpointTimeout(endpoint string, d time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.endpointTimeouts == nil {
+			c.endpointTimeouts = make(map[string]time.Duration)
+		}
+		c.endpointTimeouts[endpointName(endpoint)] = d
+	}
+}
+
+// WithDefaultTimeout sets the timeout of calls to endpoints without their own
+// timeout (see WithEndpointTimeout for the order of precedence). Like endpoint
+// timeouts, it limits the whole call, including rate limiting and retries.
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// WithContentType sets the Content-Type header sent with API requests (default: application/json).
+// The request body is always JSON encoded.
+func WithContentType(contentType string) ClientOption {
+	return func(c *Client) {
+		c.contentType = contentType
+	}
+}
+
+// WithAccept sets the Accept header sent with API requests (default: application/json)
+func WithAccept(accept string) ClientOption {
+	return func(c *Client) {
+		c.accept = accept
+	}
+}
+
+// DefaultEmptyErrorMessage describes ok:false responses that carry no error description
+const DefaultEmptyErrorMessage = "API returned ok: false"
+
+// maxErrorBodySnippet is the number of response body bytes included in errors
+const maxErrorBodySnippet = 256
+
+// WithEmptyErrorMessage sets the message of the APIError returned for ok:false
+// responses without an error or description (default: DefaultEmptyErrorMessage).
+// The start of the response body is appended to the message to help diagnose
+// such responses.
+func WithEmptyErrorMessage(message string) ClientOption {
+	return func(c *Client) {
+		c.emptyErrorMessage = message
+	}
+}
+
+// WithDefaultPageListLimit sets the limit sent by GetPageList when the request's
+// Limit is 0, instead of leaving it to the API's default of 50. The limit is
+// clamped to the range 0 to MaxPageListLimit, and 0 restores the API's default.
+func WithDefaultPageListLimit(n int) ClientOption {
+	return func(c *Client) {
+		c.defaultPageListLimit = min(max(n, 0), MaxPageListLimit)
+	}
+}
+
+// WithClock sets the source of the current time used by helpers that derive
+// dates from it, such as GetViewsToday (default: time.Now). It is meant for
+// tests that need a fixed "now"; rate limiting and retry delays always use
+// the real time.
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.clock = now
 	}
 }
 
+// now returns the current time according to the client's clock
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
 // NewClient creates a new Telegraph API client with the provided options
 func NewClient(opts ...ClientOption) *Client {
+	defaultHTTPClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
 	client := &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:  defaultHTTPClient,
 		baseURL:     "https://api.telegra.ph",
+		uploadURL:   DefaultUploadURL,
+		contentType: "application/json",
+		accept:      "application/json",
 		rateLimiter: rate.NewLimiter(rate.Limit(10), 10), // 10 requests per second by default
 		retryConfig: DefaultRetryConfig,
 	}
+	client.priorityLimiter = rate.NewLimiter(DefaultPriorityRateLimit, DefaultPriorityBurst)
 
 	for _, opt := range opts {
 		opt(client)
 	}
 
+	if client.httpClient == defaultHTTPClient {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS12
+		if client.minTLSVersion != 0 {
+			transport.TLSClientConfig.MinVersion = client.minTLSVersion
+		}
+		transport.ResponseHeaderTimeout = client.responseHeaderTimeout
+		defaultHTTPClient.Transport = transport
+	}
+
 	return client
 }
 
-// doRequest performs an HTTP request with retry logic and rate limiting
+// emptyErrorDescription describes an ok:false response body without an error
+// field, using its description field if it has one, or the configured message
+// followed by a snippet of the body otherwise
+func (c *Client) emptyErrorDescription(body []byte) string {
+	var fallback struct {
+		Description string `json:"description"`
+	}
+	if json.Unmarshal(body, &fallback) == nil && fallback.Description != "" {
+		return fallback.Description
+	}
+
+	message := c.emptyErrorMessage
+	if message == "" {
+		message = DefaultEmptyErrorMessage
+	}
+	snippet := redactToken(strings.TrimSpace(string(body)))
+	if len(snippet) > maxErrorBodySnippet {
+		snippet = snippet[:maxErrorBodySnippet] + "..."
+	}
+	return fmt.Sprintf("%s (body: %s)", message, snippet)
+}
+
+// withCallTimeout returns ctx with the deadline of a call to endpoint. The first
+// of these that is set applies:
+//
+//  1. the deadline of ctx, set by the caller
+//  2. the endpoint's timeout, set with WithEndpointTimeout
+//  3. the client's default timeout, set with WithDefaultTimeout
+//  4. the HTTP client's Timeout, which limits each attempt rather than the call
+//
+// The first three limit the whole call, including rate limiting and retries,
+// and lift the HTTP client's Timeout, so that a caller's deadline is never cut
+// short by a lower level.
+func (c *Client) withCallTimeout(ctx context.Context, endpoint string) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	if timeout := c.endpointTimeouts[endpoint]; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	if c.defaultTimeout > 0 {
+		return context.WithTimeout(ctx, c.defaultTimeout)
+	}
+	return ctx, func() {}
+}
+
+// doRequest performs an HTTP request with retry logic and rate limiting,
+// reporting metrics about the call to the metrics hook
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, data interface{}) (*http.Response, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	return c.doRequestWithHeader(ctx, method, endpoint, data, nil)
+}
+
+// doRequestWithHeader is like doRequest but adds header to every attempt.
+// Calls that consulted the page cache are reported as a cache hit when
+// answered with 304 Not Modified, and as a cache miss otherwise.
+func (c *Client) doRequestWithHeader(ctx context.Context, method, endpoint string, data interface{}, header http.Header) (*http.Response, error) {
+	metrics := RequestMetrics{Endpoint: endpointName(endpoint)}
+	start := time.Now()
+
+	// Apply the call's timeout, keeping the context alive until the body is closed
+	ctx, cancel := c.withCallTimeout(ctx, metrics.Endpoint)
+
+	resp, err := c.sendRequest(ctx, method, endpoint, data, header, &metrics)
+	if err != nil {
+		cancel()
+	} else {
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	}
+
+	if isPageCacheLookup(ctx) {
+		metrics.Cache = CacheMiss
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			metrics.Cache = CacheHit
+		}
+	}
+
+	c.stats.record(metrics)
 
+	if c.metricsHook != nil {
+		metrics.Duration = time.Since(start)
+		metrics.Err = err
+		if resp != nil {
+			metrics.StatusCode = resp.StatusCode
+		}
+		c.metricsHook(ctx, metrics)
+	}
+
+	return resp, err
+}
+
+// sendRequest sends the request, retrying failed attempts, and records metrics as it goes
+func (c *Client) sendRequest(ctx context.Context, method, endpoint string, data interface{}, header http.Header, metrics *RequestMetrics) (*http.Response, error) {
 	// Apply rate limiting
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiting failed: %w", err)
+	waitStart := time.Now()
+	err := c.waitRateLimit(ctx)
+	metrics.RateLimitWait = time.Since(waitStart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRateLimitWait, err)
 	}
 
-	var body io.Reader
+	var jsonData []byte
 	if data != nil {
-		jsonData, err := json.Marshal(data)
+		jsonData, err = json.Marshal(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request data: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
 	}
 
-	url := fmt.Sprintf("%s/%s", c.baseURL, strings.TrimPrefix(endpoint, "/"))
+	path := strings.TrimPrefix(endpoint, "/")
+	url := fmt.Sprintf("%s/%s", c.baseURL, path)
+	idempotent := c.retryConfig.RetryNonIdempotent || isIdempotentEndpoint(metrics.Endpoint)
+
+	resp, err := c.sendWithRetries(ctx, method, url, jsonData, header, idempotent, metrics)
+	if err == nil || c.fallbackBaseURL == "" || ctx.Err() != nil {
+		return resp, err
+	}
+
+	// Try the fallback once after a connection-level failure. Writes only fall
+	// back if the connection was never established, so they are not repeated.
+	var connErr *redactedError
+	if !errors.As(err, &connErr) || (!idempotent && !isDialError(err)) {
+		return nil, err
+	}
+	// The fallback request counts as the attempt after the last one
+	fallbackURL := fmt.Sprintf("%s/%s", c.fallbackBaseURL, path)
+	ctx = context.WithValue(ctx, attemptKey{}, metrics.Retries+2)
+	body, bodyHeader, fallbackErr := c.compressedBody(jsonData, header)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", fallbackErr)
+	}
+	req, fallbackErr := c.newRequest(ctx, method, fallbackURL, body, bodyHeader)
+	if fallbackErr != nil {
+		return nil, fallbackErr
+	}
+	fallbackResp, fallbackErr := c.roundTrip(req, metrics.Endpoint)
+	if fallbackErr == nil && c.rejectsCompression(fallbackResp, bodyHeader) {
+		if req, fallbackErr = c.newRequest(withUncompressedResend(ctx), method, fallbackURL, jsonData, header); fallbackErr != nil {
+			return nil, fallbackErr
+		}
+		fallbackResp, fallbackErr = c.roundTrip(req, metrics.Endpoint)
+	}
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%w; fallback failed: %w", err, &redactedError{err: fallbackErr})
+	}
+	return fallbackResp, nil
+}
+
+// sendWithRetries sends a request to url, retrying failures as configured
+func (c *Client) sendWithRetries(ctx context.Context, method, url string, jsonData []byte, header http.Header, idempotent bool, metrics *RequestMetrics) (*http.Response, error) {
+	body, bodyHeader, err := c.compressedBody(jsonData, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
 
 	var lastErr error
+	var floodWait time.Duration
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := c.calculateDelay(attempt)
+			if floodWait > 0 {
+				delay = c.floodWaitDelay(floodWait)
+				floodWait = 0
+			}
+			// Fail fast rather than sleep past the deadline only to find the
+			// context expired when the next attempt starts
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				return nil, fmt.Errorf("%w: retry delay of %s exceeds the remaining time after %d attempts: %w",
+					context.DeadlineExceeded, delay, attempt, lastErr)
+			}
+
+			metrics.Retries = attempt
+			if c.retryBudget != nil && !c.retryBudget.Allow() {
+				return nil, fmt.Errorf("%w after %d attempts: %w", ErrRetryBudgetExhausted, attempt, lastErr)
+			}
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
@@ -153,36 +785,220 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, data in
 			}
 		}
 
-		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		attemptCtx := context.WithValue(ctx, attemptKey{}, attempt+1)
+		req, err := c.newRequest(attemptCtx, method, url, body, bodyHeader)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return nil, err
 		}
 
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("User-Agent", "telegraph-go-sdk/1.0.0")
-
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.roundTrip(req, metrics.Endpoint)
+		if err == nil && c.rejectsCompression(resp, bodyHeader) {
+			// Resend uncompressed as part of the same attempt
+			body, bodyHeader = jsonData, header
+			if req, err = c.newRequest(withUncompressedResend(attemptCtx), method, url, body, bodyHeader); err != nil {
+				return nil, err
+			}
+			resp, err = c.roundTrip(req, metrics.Endpoint)
+		}
 		if err != nil {
-			lastErr = err
-			if !c.shouldRetry(err) {
-				return nil, fmt.Errorf("request failed: %w", err)
+			lastErr = &redactedError{err: err}
+			if !idempotent || !c.shouldRetry(err) {
+				return nil, fmt.Errorf("request failed: %w", lastErr)
 			}
 			continue
 		}
 
 		// Check if we should retry based on status code
-		if c.shouldRetryStatus(resp.StatusCode) {
+		if c.shouldRetryStatus(resp.StatusCode, idempotent) {
 			resp.Body.Close()
 			lastErr = fmt.Errorf("received status code %d", resp.StatusCode)
 			continue
 		}
 
+		// Flood-wait rejections and the kinds of errors configured as retryable
+		// are retried for every endpoint, as the call was not carried out. The
+		// last attempt's rejection is returned as-is.
+		if c.retriesAPIErrors() && attempt < c.retryConfig.MaxRetries && resp.StatusCode == http.StatusOK {
+			apiErr, err := peekAPIError(resp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response body: %w", err)
+			}
+			if apiErr != nil {
+				if wait, ok := apiErr.FloodWait(); ok && wait > 0 && wait <= c.retryConfig.MaxFloodWait {
+					floodWait = wait
+					lastErr = fmt.Errorf("flood wait of %s", wait)
+					continue
+				}
+				if apiErr.Kind != ErrorKindFloodWait && slices.Contains(c.retryConfig.RetryableKinds, apiErr.Kind) {
+					lastErr = apiErr
+					continue
+				}
+			}
+		}
+
 		return resp, nil
 	}
 
 	return nil, fmt.Errorf("request failed after %d attempts: %w", c.retryConfig.MaxRetries+1, lastErr)
 }
 
+// floodWaitDelay returns the delay before retrying after a flood wait: the wait
+// plus a random jitter in [0, RetryConfig.Jitter)
+func (c *Client) floodWaitDelay(wait time.Duration) time.Duration {
+	if c.retryConfig.Jitter <= 0 {
+		return wait
+	}
+	return wait + rand.N(c.retryConfig.Jitter)
+}
+
+// retriesAPIErrors reports whether any ok:false responses may be retried
+func (c *Client) retriesAPIErrors() bool {
+	return c.retryConfig.MaxFloodWait > 0 || len(c.retryConfig.RetryableKinds) > 0
+}
+
+// peekAPIError returns the error in resp, or nil if resp is not an ok:false
+// response. The body is buffered so that it can still be read by the caller.
+func peekAPIError(resp *http.Response) (*APIError, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var apiResp APIResponse
+	if json.Unmarshal(body, &apiResp) != nil || apiResp.Ok {
+		return nil, nil
+	}
+	return &APIError{Description: apiResp.Error, Kind: ParseErrorKind(apiResp.Error)}, nil
+}
+
+// newRequest builds a request attempt to url
+func (c *Client) newRequest(ctx context.Context, method, url string, jsonData []byte, header http.Header) (*http.Request, error) {
+	// Each attempt needs a fresh reader, as the previous one has been consumed
+	var body io.Reader
+	if jsonData != nil {
+		body = bytes.NewReader(jsonData)
+	}
+
+	reqURL := url
+	if c.urlRewriter != nil {
+		reqURL = c.urlRewriter(method, url)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.accept)
+	req.Header.Set("User-Agent", "telegraph-go-sdk/1.0.0")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	return req, nil
+}
+
+// isDialError reports whether err is a failure to establish a connection
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// roundTrip sends a single request attempt, unless the fault injector fails it
+func (c *Client) roundTrip(req *http.Request, endpoint string) (*http.Response, error) {
+	// The uncompressed resend belongs to an attempt the hook has already seen
+	if c.requestHook != nil && !isUncompressedResend(req.Context()) {
+		c.requestHook(req, AttemptFromContext(req.Context()))
+	}
+	if c.faultInjector != nil {
+		if err := c.faultInjector(endpoint); err != nil {
+			var status *FaultStatus
+			if errors.As(err, &status) {
+				return status.response(req), nil
+			}
+			return nil, err
+		}
+	}
+	// A deadline of the call takes precedence over the HTTP client's timeout
+	_, hasDeadline := req.Context().Deadline()
+	if c.noRedirects || (hasDeadline && c.httpClient.Timeout > 0) {
+		httpClient := *c.httpClient
+		if c.noRedirects {
+			httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		}
+		if hasDeadline {
+			httpClient.Timeout = 0
+		}
+		return httpClient.Do(req)
+	}
+	return c.httpClient.Do(req)
+}
+
+// cancelOnClose cancels a request's context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// tokenPatterns match access tokens in JSON bodies and query strings
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`("access_token"\s*:\s*")([^"]*)(")`),
+	regexp.MustCompile(`(access_token=)([^&\s"]*)()`),
+}
+
+// redactToken masks every access token found in s, keeping only the first and
+// last 4 characters of long tokens so they can still be told apart in logs
+func redactToken(s string) string {
+	if !strings.Contains(s, "access_token") {
+		return s
+	}
+	for _, pattern := range tokenPatterns {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			parts := pattern.FindStringSubmatch(match)
+			return parts[1] + maskToken(parts[2]) + parts[3]
+		})
+	}
+	return s
+}
+
+// maskToken hides all but the first and last 4 characters of a token
+func maskToken(token string) string {
+	if len(token) <= 12 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + "..." + token[len(token)-4:]
+}
+
+// redactedError masks access tokens in the message of the wrapped error
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return redactToken(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// endpointName returns the API method name of an endpoint, e.g. "getPage" for "/getPage?path=x"
+func endpointName(endpoint string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "?")
+	return name
+}
+
 func (c *Client) calculateDelay(attempt int) time.Duration {
 	delay := c.retryConfig.InitialDelay * time.Duration(1<<uint(attempt-1)) * time.Duration(c.retryConfig.Multiplier)
 
@@ -200,9 +1016,25 @@ func (c *Client) shouldRetry(err error) bool {
 }
 
 // shouldRetryStatus determines if a request should be retried based on status code
-func (c *Client) shouldRetryStatus(statusCode int) bool {
-	// Retry on 5xx errors and 429 (Too Many Requests)
-	return statusCode >= 500 || statusCode == 429
+func (c *Client) shouldRetryStatus(statusCode int, idempotent bool) bool {
+	// Retry on 429 (Too Many Requests) always, and on 5xx errors for idempotent requests
+	if statusCode == 429 {
+		return true
+	}
+	return idempotent && statusCode >= 500
+}
+
+// idempotentEndpoints lists the read-only API methods that are safe to retry
+var idempotentEndpoints = map[string]bool{
+	"getPage":        true,
+	"getPageList":    true,
+	"getViews":       true,
+	"getAccountInfo": true,
+}
+
+// isIdempotentEndpoint reports whether the API method can be retried without side effects
+func isIdempotentEndpoint(name string) bool {
+	return idempotentEndpoints[name]
 }
 
 // parseResponse parses the API response and handles errors
@@ -219,9 +1051,11 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 		if err := json.Unmarshal(body, &apiErr); err != nil {
 			return &APIError{
 				Code:        resp.StatusCode,
-				Description: string(body),
+				Description: redactToken(string(body)),
+				Kind:        ParseErrorKind(string(body)),
 			}
 		}
+		apiErr.Kind = ParseErrorKind(apiErr.Description)
 		return &apiErr
 	}
 
@@ -231,9 +1065,14 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 	}
 
 	if !apiResp.Ok {
+		description := apiResp.Error
+		if description == "" {
+			description = c.emptyErrorDescription(body)
+		}
 		return &APIError{
 			Code:        0,
-			Description: "API returned ok: false",
+			Description: description,
+			Kind:        ParseErrorKind(description),
 		}
 	}
 
@@ -265,6 +1104,9 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 //		AuthorURL:  "https://example.com",
 //	})
 func (c *Client) CreateAccount(ctx context.Context, req *CreateAccountRequest) (*Account, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -291,75 +1133,253 @@ func (c *Client) CreateAccount(ctx context.Context, req *CreateAccountRequest) (
 //
 //	account, err := client.EditAccountInfo(ctx, &telegraph.EditAccountInfoRequest{
 //		AccessToken: "your-access-token",
-//		ShortName:   "UpdatedBlog",
-//		AuthorName:  "Jane Doe",
+//		ShortName:   "UpdatedBlog",
+//		AuthorName:  "Jane Doe",
+//	})
+func (c *Client) EditAccountInfo(ctx context.Context, req *EditAccountInfoRequest) (*Account, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/editAccountInfo", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if err := c.parseResponse(resp, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// GetAccountInfo gets account information
+//
+// This method is used to get information about a Telegraph account.
+// Returns an Account object on success.
+//
+// Example:
+//
+//	account, err := client.GetAccountInfo(ctx, &telegraph.GetAccountInfoRequest{
+//		AccessToken: "your-access-token",
+//		Fields:      []string{"short_name", "author_name", "page_count"},
+//	})
+func (c *Client) GetAccountInfo(ctx context.Context, req *GetAccountInfoRequest) (*Account, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/getAccountInfo", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if err := c.parseResponse(resp, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// SetDefaultAuthorFromAccount fetches the account's author name and URL once and
+// caches them as the default author for CreatePage and EditPage. Requests that
+// set their own AuthorName or AuthorURL keep those values.
+//
+// Example:
+//
+//	if err := client.SetDefaultAuthorFromAccount(ctx, "your-access-token"); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *Client) SetDefaultAuthorFromAccount(ctx context.Context, accessToken string) error {
+	account, err := c.GetAccountInfo(ctx, &GetAccountInfoRequest{
+		AccessToken: accessToken,
+		Fields:      []string{"author_name", "author_url"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch default author: %w", err)
+	}
+
+	c.stateMu.Lock()
+	c.defaultAuthor = &Account{
+		AuthorName: account.AuthorName,
+		AuthorURL:  account.AuthorURL,
+	}
+	c.stateMu.Unlock()
+
+	return nil
+}
+
+// defaultAuthorFields returns the cached default author, if any
+func (c *Client) defaultAuthorFields() (string, string) {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+
+	if c.defaultAuthor == nil {
+		return "", ""
+	}
+	return c.defaultAuthor.AuthorName, c.defaultAuthor.AuthorURL
+}
+
+// applyDefaultAuthor fills empty author fields from the defaults
+func applyDefaultAuthor(name, authorURL, defaultName, defaultURL string) (string, string) {
+	if name == "" {
+		name = defaultName
+	}
+	if authorURL == "" {
+		authorURL = defaultURL
+	}
+	return name, authorURL
+}
+
+// CreatePage creates a new Telegraph page
+//
+// This method is used to create a new Telegraph page. Returns a Page object on success.
+//
+// Example:
+//
+//	page, err := client.CreatePage(ctx, &telegraph.CreatePageRequest{
+//		AccessToken: "your-access-token",
+//		Title:       "My Article",
+//		Content: []telegraph.Node{
+//			{Tag: "p", Children: []telegraph.Node{{Content: "Hello, World!"}}},
+//		},
 //	})
-func (c *Client) EditAccountInfo(ctx context.Context, req *EditAccountInfoRequest) (*Account, error) {
+func (c *Client) CreatePage(ctx context.Context, req *CreatePageRequest) (*Page, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if name, authorURL := c.defaultAuthorFields(); name != "" || authorURL != "" {
+		withAuthor := *req
+		withAuthor.AuthorName, withAuthor.AuthorURL = applyDefaultAuthor(req.AuthorName, req.AuthorURL, name, authorURL)
+		req = &withAuthor
+	}
+	if returnContent := c.resolveReturnContent(req.ReturnContent); returnContent != req.ReturnContent {
+		withReturnContent := *req
+		withReturnContent.ReturnContent = returnContent
+		req = &withReturnContent
+	}
+
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "/editAccountInfo", req)
+	resp, err := c.doRequest(ctx, "POST", "/createPage", req)
 	if err != nil {
 		return nil, err
 	}
 
-	var account Account
-	if err := c.parseResponse(resp, &account); err != nil {
+	var page Page
+	if err := c.parseResponse(resp, &page); err != nil {
 		return nil, err
 	}
+	if c.validatePages {
+		if err := page.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
-	return &account, nil
+	return &page, nil
 }
 
-// GetAccountInfo gets account information
+// CreateLinkListPage creates a page whose content is a bulleted list of links,
+// e.g. for link-in-bio pages
 //
-// This method is used to get information about a Telegraph account.
-// Returns an Account object on success.
+// Links without text show their URL. Options such as WithPageAuthor apply as
+// for CreatePageRaw.
 //
 // Example:
 //
-//	account, err := client.GetAccountInfo(ctx, &telegraph.GetAccountInfoRequest{
-//		AccessToken: "your-access-token",
-//		Fields:      []string{"short_name", "author_name", "page_count"},
+//	page, err := client.CreateLinkListPage(ctx, accessToken, "My Links", []telegraph.LinkItem{
+//		{Text: "Blog", URL: "https://example.com/blog"},
+//		{Text: "GitHub", URL: "https://github.com/example"},
 //	})
-func (c *Client) GetAccountInfo(ctx context.Context, req *GetAccountInfoRequest) (*Account, error) {
-	if err := req.Validate(); err != nil {
-		return nil, err
+func (c *Client) CreateLinkListPage(ctx context.Context, accessToken, title string, links []LinkItem, opts ...PageOption) (*Page, error) {
+	if len(links) == 0 {
+		return nil, fmt.Errorf("at least one link is required")
+	}
+	for i, link := range links {
+		if link.URL == "" {
+			return nil, fmt.Errorf("links[%d]: url is required", i)
+		}
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "/getAccountInfo", req)
-	if err != nil {
-		return nil, err
+	req := &CreatePageRequest{
+		AccessToken: accessToken,
+		Title:       title,
+		Content:     NewContentBuilder().AddLinkList(links).Build(),
 	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	return c.CreatePage(ctx, req)
+}
 
-	var account Account
-	if err := c.parseResponse(resp, &account); err != nil {
-		return nil, err
+// PageOption sets an optional field of a page created by CreatePageRaw or CreateLinkListPage
+type PageOption func(*CreatePageRequest)
+
+// WithPageAuthor sets the author name and URL of the page
+func WithPageAuthor(name, authorURL string) PageOption {
+	return func(r *CreatePageRequest) {
+		r.AuthorName = name
+		r.AuthorURL = authorURL
 	}
+}
 
-	return &account, nil
+// WithPageReturnContent requests the page content in the response
+func WithPageReturnContent() PageOption {
+	return func(r *CreatePageRequest) {
+		r.ReturnContent = true
+	}
 }
 
-// CreatePage creates a new Telegraph page
+// createPageRawRequest is a CreatePageRequest whose content is already serialized
+type createPageRawRequest struct {
+	*CreatePageRequest
+	Content json.RawMessage `json:"content"`
+}
+
+// CreatePageRaw creates a new Telegraph page from content that is already serialized as JSON
 //
-// This method is used to create a new Telegraph page. Returns a Page object on success.
+// contentJSON must be a non-empty JSON array of nodes, each either a string or an
+// object. It is sent as-is, without being decoded into Nodes and re-encoded.
 //
 // Example:
 //
-//	page, err := client.CreatePage(ctx, &telegraph.CreatePageRequest{
-//		AccessToken: "your-access-token",
-//		Title:       "My Article",
-//		Content: []telegraph.Node{
-//			{Tag: "p", Children: []telegraph.Node{{Content: "Hello, World!"}}},
-//		},
-//	})
-func (c *Client) CreatePage(ctx context.Context, req *CreatePageRequest) (*Page, error) {
-	if err := req.Validate(); err != nil {
+//	page, err := client.CreatePageRaw(ctx, "your-access-token", "My Article",
+//		json.RawMessage(`[{"tag":"p","children":["Hello, World!"]}]`),
+//		telegraph.WithPageAuthor("John Doe", "https://example.com"),
+//	)
+func (c *Client) CreatePageRaw(ctx context.Context, accessToken, title string, contentJSON json.RawMessage, opts ...PageOption) (*Page, error) {
+	req := &CreatePageRequest{
+		AccessToken: accessToken,
+		Title:       title,
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	name, authorURL := c.defaultAuthorFields()
+	req.AuthorName, req.AuthorURL = applyDefaultAuthor(req.AuthorName, req.AuthorURL, name, authorURL)
+	req.ReturnContent = c.resolveReturnContent(req.ReturnContent)
+
+	if err := req.validateFields(); err != nil {
+		return nil, err
+	}
+	if err := validateContentJSON(contentJSON); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "/createPage", req)
+	resp, err := c.doRequest(ctx, "POST", "/createPage", &createPageRawRequest{
+		CreatePageRequest: req,
+		Content:           contentJSON,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -368,10 +1388,33 @@ func (c *Client) CreatePage(ctx context.Context, req *CreatePageRequest) (*Page,
 	if err := c.parseResponse(resp, &page); err != nil {
 		return nil, err
 	}
+	if c.validatePages {
+		if err := page.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	return &page, nil
 }
 
+// validateContentJSON checks that data is a non-empty JSON array of strings and objects
+func validateContentJSON(data json.RawMessage) error {
+	var nodes []json.RawMessage
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return fmt.Errorf("content must be a JSON array of nodes: %w", err)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("content is required")
+	}
+	for i, node := range nodes {
+		trimmed := bytes.TrimSpace(node)
+		if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '"') {
+			return fmt.Errorf("content[%d] must be a string or a node object", i)
+		}
+	}
+	return nil
+}
+
 // EditPage edits an existing Telegraph page
 //
 // This method is used to edit an existing Telegraph page. Returns a Page object on success.
@@ -387,6 +1430,20 @@ func (c *Client) CreatePage(ctx context.Context, req *CreatePageRequest) (*Page,
 //		},
 //	})
 func (c *Client) EditPage(ctx context.Context, req *EditPageRequest) (*Page, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if name, authorURL := c.defaultAuthorFields(); name != "" || authorURL != "" {
+		withAuthor := *req
+		withAuthor.AuthorName, withAuthor.AuthorURL = applyDefaultAuthor(req.AuthorName, req.AuthorURL, name, authorURL)
+		req = &withAuthor
+	}
+	if returnContent := c.resolveReturnContent(req.ReturnContent); returnContent != req.ReturnContent {
+		withReturnContent := *req
+		withReturnContent.ReturnContent = returnContent
+		req = &withReturnContent
+	}
+
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -400,10 +1457,60 @@ func (c *Client) EditPage(ctx context.Context, req *EditPageRequest) (*Page, err
 	if err := c.parseResponse(resp, &page); err != nil {
 		return nil, err
 	}
+	if c.validatePages {
+		if err := page.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	c.pageCache.invalidate(req.Path)
 
 	return &page, nil
 }
 
+// EditOrCreatePage edits the page at path, creating it if it does not exist
+//
+// The page is edited first; when the API reports PAGE_NOT_FOUND, or path is
+// empty, a new page is created from req instead. This makes repeated publishing
+// of the same document idempotent.
+//
+// Example:
+//
+//	page, err := client.EditOrCreatePage(ctx, &telegraph.CreatePageRequest{
+//		AccessToken: "your-access-token",
+//		Title:       "Docs",
+//		Content:     content,
+//	}, "Docs-12-15")
+func (c *Client) EditOrCreatePage(ctx context.Context, req *CreatePageRequest, path string) (*Page, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if path == "" {
+		return c.CreatePage(ctx, req)
+	}
+
+	page, err := c.EditPage(ctx, &EditPageRequest{
+		AccessToken:   req.AccessToken,
+		Path:          path,
+		Title:         req.Title,
+		AuthorName:    req.AuthorName,
+		AuthorURL:     req.AuthorURL,
+		Content:       req.Content,
+		ReturnContent: req.ReturnContent,
+		RawTitle:      req.RawTitle,
+	})
+	if err == nil {
+		return page, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != ErrorKindPageNotFound {
+		return nil, err
+	}
+
+	return c.CreatePage(ctx, req)
+}
+
 // GetPage gets a Telegraph page
 //
 // This method is used to get a Telegraph page. Returns a Page object on success.
@@ -415,6 +1522,20 @@ func (c *Client) EditPage(ctx context.Context, req *EditPageRequest) (*Page, err
 //		ReturnContent: true,
 //	})
 func (c *Client) GetPage(ctx context.Context, req *GetPageRequest) (*Page, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
+	if returnContent := c.resolveReturnContent(req.ReturnContent); returnContent != req.ReturnContent {
+		withReturnContent := *req
+		withReturnContent.ReturnContent = returnContent
+		req = &withReturnContent
+	}
+	return c.getPage(ctx, req)
+}
+
+// getPage gets a page like GetPage, but sends req.ReturnContent as-is. Helpers
+// that need the content, or do not, call it so WithReturnContent cannot interfere.
+func (c *Client) getPage(ctx context.Context, req *GetPageRequest) (*Page, error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -427,6 +1548,10 @@ func (c *Client) GetPage(ctx context.Context, req *GetPageRequest) (*Page, error
 	}
 
 	endpoint := fmt.Sprintf("/getPage?%s", params.Encode())
+	if c.pageCache != nil {
+		return c.getCachedPage(ctx, req, endpoint)
+	}
+
 	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -440,6 +1565,172 @@ func (c *Client) GetPage(ctx context.Context, req *GetPageRequest) (*Page, error
 	return &page, nil
 }
 
+// PageExists reports whether a Telegraph page exists
+//
+// This method issues a getPage request without content. A PAGE_NOT_FOUND error
+// from the API is reported as false with a nil error; any other error is returned.
+//
+// Example:
+//
+//	exists, err := client.PageExists(ctx, "My-Article-12-15")
+func (c *Client) PageExists(ctx context.Context, path string) (bool, error) {
+	_, err := c.getPage(ctx, &GetPageRequest{
+		Path:          path,
+		ReturnContent: false,
+	})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Kind == ErrorKindPageNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// canEditRequest is a getPage request on behalf of an account, which makes the
+// API report whether the account can edit the page
+type canEditRequest struct {
+	AccessToken   string `json:"access_token"`
+	Path          string `json:"path"`
+	ReturnContent bool   `json:"return_content,omitempty"`
+}
+
+// CanEditPage reports whether the account of accessToken can edit the page at path
+//
+// The page is fetched without content on behalf of the account, and its
+// can_edit field is returned, so that an edit can be ruled out before it is
+// attempted. The access token is sent in the request body rather than the URL.
+// A missing page is reported as an error, like GetPage.
+//
+// Example:
+//
+//	ok, err := client.CanEditPage(ctx, token, "My-Article-12-15")
+//	if err == nil && !ok {
+//		return fmt.Errorf("page belongs to another account")
+//	}
+func (c *Client) CanEditPage(ctx context.Context, accessToken, path string) (bool, error) {
+	if accessToken == "" {
+		return false, fmt.Errorf("access_token is required")
+	}
+	if err := ValidatePagePath(path); err != nil {
+		return false, err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/getPage", &canEditRequest{AccessToken: accessToken, Path: path})
+	if err != nil {
+		return false, err
+	}
+
+	var page Page
+	if err := c.parseResponse(resp, &page); err != nil {
+		return false, err
+	}
+	return page.CanEdit, nil
+}
+
+// EditableContent fetches the page at path for editing on behalf of the account of accessToken
+//
+// The page is fetched with its content, bypassing the page cache, and returned
+// together with a ContentBuilder seeded from a copy of the content, so that the
+// content can be modified and submitted with EditPage. The page's CanEdit field
+// reports whether the account can edit it; EditableContent does not fail for
+// pages of other accounts. The access token is sent in the request body rather
+// than the URL.
+//
+// Example:
+//
+//	cb, page, err := client.EditableContent(ctx, token, "My-Article-12-15")
+//	if err != nil {
+//		return err
+//	}
+//	_, err = client.EditPage(ctx, &telegraph.EditPageRequest{
+//		AccessToken: token,
+//		Path:        page.Path,
+//		Title:       page.Title,
+//		Content:     cb.AddParagraph("Updated").Build(),
+//	})
+func (c *Client) EditableContent(ctx context.Context, accessToken, path string) (*ContentBuilder, *Page, error) {
+	if accessToken == "" {
+		return nil, nil, fmt.Errorf("access_token is required")
+	}
+	if err := ValidatePagePath(path); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/getPage", &canEditRequest{
+		AccessToken:   accessToken,
+		Path:          path,
+		ReturnContent: true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var page Page
+	if err := c.parseResponse(resp, &page); err != nil {
+		return nil, nil, err
+	}
+	return NewContentBuilderFromNodes(page.Content), &page, nil
+}
+
+// CompareOption configures a single ContentChanged call
+type CompareOption func(*compareOptions)
+
+// compareOptions holds the settings of a ContentChanged call
+type compareOptions struct {
+	ignoreWhitespace bool
+}
+
+// IgnoreWhitespace treats runs of whitespace in text as a single space and
+// ignores whitespace-only text, so reformatting alone does not count as a change
+func IgnoreWhitespace() CompareOption {
+	return func(o *compareOptions) {
+		o.ignoreWhitespace = true
+	}
+}
+
+// ContentChanged reports whether local content differs from the published
+// content of the page at path
+//
+// The published content is fetched on behalf of the account of accessToken,
+// bypassing the page cache, and compared with NodesEqual. The access token is
+// sent in the request body rather than the URL. Use it to skip needless edits:
+//
+//	changed, err := client.ContentChanged(ctx, token, path, content, telegraph.IgnoreWhitespace())
+//	if err == nil && changed {
+//		_, err = client.EditPage(ctx, req)
+//	}
+func (c *Client) ContentChanged(ctx context.Context, accessToken, path string, local []Node, opts ...CompareOption) (bool, error) {
+	if accessToken == "" {
+		return false, fmt.Errorf("access_token is required")
+	}
+	if err := ValidatePagePath(path); err != nil {
+		return false, err
+	}
+
+	var o compareOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/getPage", &canEditRequest{
+		AccessToken:   accessToken,
+		Path:          path,
+		ReturnContent: true,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var page Page
+	if err := c.parseResponse(resp, &page); err != nil {
+		return false, err
+	}
+	return !nodesEqual(page.Content, local, o.ignoreWhitespace), nil
+}
+
 // GetPageList gets a list of pages belonging to a Telegraph account
 //
 // This method is used to get a list of pages belonging to a Telegraph account.
@@ -453,9 +1744,17 @@ func (c *Client) GetPage(ctx context.Context, req *GetPageRequest) (*Page, error
 //		Limit:       10,
 //	})
 func (c *Client) GetPageList(ctx context.Context, req *GetPageListRequest) (*PageList, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	if req.Limit == 0 && c.defaultPageListLimit > 0 {
+		withLimit := *req
+		withLimit.Limit = c.defaultPageListLimit
+		req = &withLimit
+	}
 
 	resp, err := c.doRequest(ctx, "POST", "/getPageList", req)
 	if err != nil {
@@ -473,7 +1772,9 @@ func (c *Client) GetPageList(ctx context.Context, req *GetPageListRequest) (*Pag
 // GetViews gets the number of views for a Telegraph page
 //
 // This method is used to get the number of views for a Telegraph page.
-// Returns a PageViews object on success.
+// Returns a PageViews object on success. When no date fields are set, the
+// total number of views for the page's whole lifetime is returned; use
+// GetViewsToday for the current day's views.
 //
 // Example:
 //
@@ -485,6 +1786,9 @@ func (c *Client) GetPageList(ctx context.Context, req *GetPageListRequest) (*Pag
 //		Hour: 10,
 //	})
 func (c *Client) GetViews(ctx context.Context, req *GetViewsRequest) (*PageViews, error) {
+	if req == nil {
+		return nil, ErrNilRequest
+	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -502,17 +1806,96 @@ func (c *Client) GetViews(ctx context.Context, req *GetViewsRequest) (*PageViews
 	return &views, nil
 }
 
+// GetViewsToday gets the number of views for a Telegraph page for the current UTC day
+//
+// The current day is taken from the client's clock (see WithClock).
+//
+// Example:
+//
+//	views, err := client.GetViewsToday(ctx, "My-Article-12-15")
+func (c *Client) GetViewsToday(ctx context.Context, path string) (*PageViews, error) {
+	now := c.now().UTC()
+	return c.GetViews(ctx, &GetViewsRequest{
+		Path:  path,
+		Year:  now.Year(),
+		Month: int(now.Month()),
+		Day:   now.Day(),
+	})
+}
+
+// DefaultMaxDepth is the default maximum element nesting depth accepted by ConvertHTMLToPage
+const DefaultMaxDepth = 256
+
 // HTMLToPageOptions represents options for converting HTML to a Telegraph Page
 type HTMLToPageOptions struct {
 	AuthorName string
 	AuthorURL  string
+	// MaxDepth is the maximum element nesting depth allowed in the body (default: DefaultMaxDepth)
+	MaxDepth int
+	// CollapseBreaks merges runs of two or more consecutive br tags into a single paragraph break
+	CollapseBreaks bool
+	// AllowedAttrs lists extra attributes preserved during conversion, in addition to href and src.
+	// Entries are either "attr" to allow an attribute on every tag, or "tag:attr" to allow it on
+	// one HTML tag only. A trailing "*" matches a prefix, e.g. "iframe:data-*".
+	AllowedAttrs []string
+	// UploadImages rehosts every img on telegra.ph and rewrites its src to the uploaded path.
+	// Images already hosted on telegra.ph are left as-is.
+	UploadImages bool
+	// ImageFetcher opens images for upload (default: download http and https URLs)
+	ImageFetcher ImageFetcher
+	// IgnoreUploadErrors keeps the original src of images that fail to upload
+	// instead of failing the conversion
+	IgnoreUploadErrors bool
+}
+
+// allowsAttr reports whether attr should be preserved on the given HTML tag
+func (o *HTMLToPageOptions) allowsAttr(tag, attr string) bool {
+	// Only 'href' and 'src' attributes are supported by default
+	if attr == "href" || attr == "src" {
+		return true
+	}
+	if o == nil {
+		return false
+	}
+
+	for _, allowed := range o.AllowedAttrs {
+		if allowedTag, allowedAttr, ok := strings.Cut(allowed, ":"); ok {
+			if allowedTag != tag {
+				continue
+			}
+			allowed = allowedAttr
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(attr, prefix) {
+				return true
+			}
+		} else if allowed == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// maxDepth returns the configured maximum nesting depth, falling back to DefaultMaxDepth
+func (o *HTMLToPageOptions) maxDepth() int {
+	if o == nil || o.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return o.MaxDepth
 }
 
 // ConvertHTMLToPage converts an HTML string into a Telegraph Page object.
 // It extracts metadata like title, author name, and author URL from meta tags,
 // and converts the HTML body into a slice of Node objects, handling supported
-// and unsupported tags, and skipping script tags.
+// and unsupported tags, and skipping script tags. Documents nested deeper than
+// the configured MaxDepth are rejected with an error.
 func (c *Client) ConvertHTMLToPage(htmlContent string, opts *HTMLToPageOptions) (*Page, error) {
+	return c.ConvertHTMLToPageContext(context.Background(), htmlContent, opts)
+}
+
+// ConvertHTMLToPageContext is like ConvertHTMLToPage but uses ctx for any API
+// calls made during conversion, such as image uploads.
+func (c *Client) ConvertHTMLToPageContext(ctx context.Context, htmlContent string, opts *HTMLToPageOptions) (*Page, error) {
 	doc, err := html.Parse(strings.NewReader(htmlContent))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -524,19 +1907,29 @@ func (c *Client) ConvertHTMLToPage(htmlContent string, opts *HTMLToPageOptions)
 	c.extractMetadata(doc, page, opts)
 
 	// Parse body content
-	bodyContent, err := c.parseHTMLBody(doc)
+	bodyContent, err := c.parseHTMLBody(doc, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML body: %w", err)
 	}
 	page.Content = bodyContent
 
+	if opts != nil && opts.UploadImages {
+		if err := c.uploadImages(ctx, page.Content, opts); err != nil {
+			return nil, err
+		}
+	}
+
 	return page, nil
 }
 
 // extractMetadata extracts title, author name, and author URL from HTML meta tags.
 func (c *Client) extractMetadata(doc *html.Node, page *Page, opts *HTMLToPageOptions) {
-	var f func(*html.Node)
-	f = func(n *html.Node) {
+	// Walk the tree iteratively so that deeply nested documents cannot exhaust the stack
+	stack := []*html.Node{doc}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
 		if n.Type == html.ElementNode && n.Data == "title" && n.FirstChild != nil {
 			page.Title = n.FirstChild.Data
 		}
@@ -563,11 +1956,10 @@ func (c *Client) extractMetadata(doc *html.Node, page *Page, opts *HTMLToPageOpt
 				page.Description = content
 			}
 		}
-		for child := n.FirstChild; child != nil; child = child.NextSibling {
-			f(child)
+		for child := n.LastChild; child != nil; child = child.PrevSibling {
+			stack = append(stack, child)
 		}
 	}
-	f(doc)
 
 	if opts != nil {
 		if opts.AuthorName != "" {
@@ -580,42 +1972,74 @@ func (c *Client) extractMetadata(doc *html.Node, page *Page, opts *HTMLToPageOpt
 }
 
 // parseHTMLBody parses the HTML body and converts it into a slice of Node objects.
-func (c *Client) parseHTMLBody(doc *html.Node) ([]Node, error) {
+func (c *Client) parseHTMLBody(doc *html.Node, opts *HTMLToPageOptions) ([]Node, error) {
 	var body *html.Node
-	var f func(*html.Node)
-	f = func(n *html.Node) {
+	stack := []*html.Node{doc}
+	for len(stack) > 0 && body == nil {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
 		if n.Type == html.ElementNode && n.Data == "body" {
 			body = n
-			return
+			continue
 		}
-		for child := n.FirstChild; child != nil; child = child.NextSibling {
-			f(child)
+		for child := n.LastChild; child != nil; child = child.PrevSibling {
+			stack = append(stack, child)
 		}
 	}
-	f(doc)
 
 	if body == nil {
 		return nil, fmt.Errorf("HTML document has no body tag")
 	}
 
-	return c.htmlNodeToTelegraphNodes(body), nil
+	return c.htmlNodeToTelegraphNodes(body, opts, 0, false)
 }
 
 // htmlNodeToTelegraphNodes recursively converts an HTML node and its children
 // into Telegraph Node objects. It skips script tags and tries to map
-// unsupported tags to semantically closest supported tags.
-func (c *Client) htmlNodeToTelegraphNodes(n *html.Node) []Node {
-	var nodes []Node
+// unsupported tags to semantically closest supported tags. The depth argument
+// is the element nesting level of n, and descending past opts.maxDepth() fails.
+// When inline is set, n is inside an inline tag such as a or strong, and block
+// elements are unwrapped since Telegraph does not allow them there.
+func (c *Client) htmlNodeToTelegraphNodes(n *html.Node, opts *HTMLToPageOptions, depth int, inline bool) ([]Node, error) {
+	buf := getNodeBuffer()
+	defer putNodeBuffer(buf)
+
+	if err := c.convertHTMLChildren(n, opts, depth, inline, buf); err != nil {
+		return nil, err
+	}
+	if len(buf.nodes) == 0 {
+		return nil, nil
+	}
+
+	// Copy the result out of the pooled buffer, so it never aliases pooled memory
+	return append([]Node(nil), buf.nodes...), nil
+}
+
+// convertHTMLChildren appends the converted children of n to buf. It implements
+// htmlNodeToTelegraphNodes, converting each level of the tree into a pooled
+// buffer that is released once the level has been copied into its parent.
+func (c *Client) convertHTMLChildren(n *html.Node, opts *HTMLToPageOptions, depth int, inline bool, buf *nodeBuffer) error {
+	if n == nil {
+		return nil
+	}
+
+	nodes := buf.nodes
+	defer func() { buf.nodes = nodes }()
+
 	for child := n.FirstChild; child != nil; child = child.NextSibling {
-		if child.Type == html.TextNode {
+		switch child.Type {
+		case html.TextNode:
 			// Do not trim space here; Telegraph API can have spaces in text nodes
 			if child.Data != "" {
 				nodes = append(nodes, Node{Content: child.Data})
 			}
 			continue
-		}
-
-		if child.Type != html.ElementNode {
+		case html.CommentNode, html.DoctypeNode:
+			// Comments and doctypes carry no content, so their text must never leak into the page
+			continue
+		case html.ElementNode:
+			// Converted below
+		default:
 			continue
 		}
 
@@ -624,23 +2048,54 @@ func (c *Client) htmlNodeToTelegraphNodes(n *html.Node) []Node {
 			continue
 		}
 
+		if depth+1 > opts.maxDepth() {
+			return fmt.Errorf("content exceeds maximum nesting depth of %d", opts.maxDepth())
+		}
+
 		node := Node{
 			Tag: c.mapTag(child.Data),
 		}
-
-		// Add attributes
-		if len(child.Attr) > 0 {
-			node.Attrs = make(map[string]string)
-			for _, a := range child.Attr {
-				// Only 'href' and 'src' attributes are supported
-				if a.Key == "href" || a.Key == "src" {
-					node.Attrs[a.Key] = a.Val
+		unwrap := inline && blockTags[node.Tag]
+		_, custom := c.tagMappings[child.Data]
+		// Definition terms are shown in bold
+		bold := child.Data == "dt" && !custom
+
+		// Add attributes, allocating the map only when some are kept. Keys are
+		// matched and kept in lower case, whatever the case of the source.
+		for _, a := range child.Attr {
+			key := strings.ToLower(a.Key)
+			if opts.allowsAttr(child.Data, key) {
+				if node.Attrs == nil {
+					node.Attrs = make(map[string]string, len(child.Attr))
 				}
+				node.Attrs[key] = a.Val
 			}
 		}
 
-		// Recursively convert children
-		children := c.htmlNodeToTelegraphNodes(child)
+		// Recursively convert children. Their Node values are copied out of the
+		// buffer below, so it can be released as soon as this element is built.
+		childBuf := getNodeBuffer()
+		if err := c.convertHTMLChildren(child, opts, depth+1, inline || bold || inlineTags[node.Tag], childBuf); err != nil {
+			putNodeBuffer(childBuf)
+			return err
+		}
+		children := childBuf.nodes
+
+		// Block elements inside inline ones, e.g. <a><div>, keep only their children
+		if unwrap {
+			nodes = append(nodes, children...)
+			putNodeBuffer(childBuf)
+			continue
+		}
+
+		// Definition lists have no Telegraph equivalent, so the dl wrapper is
+		// dropped and its terms and definitions are kept in order at this level
+		if child.Data == "dl" && !custom {
+			nodes = append(nodes, children...)
+			putNodeBuffer(childBuf)
+			continue
+		}
+
 		if len(children) > 0 {
 			// If the current node is a simple text wrapper like p, and its only child
 			// is a text node, directly assign the content to the current node to avoid
@@ -664,13 +2119,93 @@ func (c *Client) htmlNodeToTelegraphNodes(n *html.Node) []Node {
 			}
 		}
 
+		putNodeBuffer(childBuf)
+
+		if bold {
+			node.Children = []interface{}{Node{Tag: "strong", Children: node.Children}}
+		}
+
 		nodes = append(nodes, node)
 	}
-	return nodes
+
+	if opts != nil && opts.CollapseBreaks {
+		nodes = collapseBreaks(nodes)
+	}
+	return nil
+}
+
+// maxPooledNodes is the capacity above which node buffers are not returned to the pool
+const maxPooledNodes = 1024
+
+// nodeBuffer holds the nodes converted at one level of an HTML tree
+type nodeBuffer struct {
+	nodes []Node
+}
+
+// nodeBufferPool reuses the buffers of convertHTMLChildren across levels and conversions
+var nodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &nodeBuffer{nodes: make([]Node, 0, 16)}
+	},
+}
+
+func getNodeBuffer() *nodeBuffer {
+	return nodeBufferPool.Get().(*nodeBuffer)
+}
+
+// putNodeBuffer clears buf, dropping its references to converted nodes, and returns it to the pool
+func putNodeBuffer(buf *nodeBuffer) {
+	if cap(buf.nodes) > maxPooledNodes {
+		return
+	}
+	clear(buf.nodes)
+	buf.nodes = buf.nodes[:0]
+	nodeBufferPool.Put(buf)
+}
+
+// collapseBreaks replaces every run of two or more br nodes, optionally separated
+// by whitespace-only text, with exactly two br nodes (a single paragraph break).
+// Lone br nodes are left untouched.
+func collapseBreaks(nodes []Node) []Node {
+	var result []Node
+	for i := 0; i < len(nodes); {
+		if nodes[i].Tag != "br" {
+			result = append(result, nodes[i])
+			i++
+			continue
+		}
+
+		// Find the last br of the run starting at i
+		last, count := i, 0
+		for j := i; j < len(nodes); j++ {
+			if nodes[j].Tag == "br" {
+				last = j
+				count++
+				continue
+			}
+			if nodes[j].Tag == "" && strings.TrimSpace(nodes[j].Content) == "" {
+				continue
+			}
+			break
+		}
+
+		if count >= 2 {
+			result = append(result, Node{Tag: "br"}, Node{Tag: "br"})
+		} else {
+			result = append(result, nodes[i:last+1]...)
+		}
+		i = last + 1
+	}
+	return result
 }
 
 // mapTag maps unsupported HTML tags to the closest semantically supported Telegraph tags.
+// Mappings registered with WithTagMapping are consulted first.
 func (c *Client) mapTag(tag string) string {
+	if mapped, ok := c.tagMappings[tag]; ok {
+		return mapped
+	}
+
 	switch tag {
 	case "h1", "h2":
 		return "h3" // Map h1, h2 to h3 as h3 is the highest supported heading
@@ -682,15 +2217,12 @@ func (c *Client) mapTag(tag string) string {
 		return tag
 	case "div", "span": // Generic containers, try to map to paragraph if they contain text
 		return "p"
+	case "dt": // Definition terms become (bold) paragraphs
+		return "p"
+	case "dd": // Definitions are set apart as blockquotes
+		return "blockquote"
 	default:
 		// Check if the tag is explicitly supported by Telegraph API.
-		// Available tags: a, aside, b, blockquote, br, code, em, figcaption, figure, h3, h4, hr, i, iframe, img, li, ol, p, pre, s, strong, u, ul, video.
-		supportedTags := map[string]bool{
-			"a": true, "aside": true, "b": true, "blockquote": true, "br": true, "code": true,
-			"em": true, "figcaption": true, "figure": true, "h3": true, "h4": true, "hr": true,
-			"i": true, "iframe": true, "img": true, "li": true, "ol": true, "p": true, "pre": true,
-			"s": true, "strong": true, "u": true, "ul": true, "video": true,
-		}
 		if supportedTags[tag] {
 			return tag
 		}
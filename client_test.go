@@ -2,16 +2,22 @@ package telegraph
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
 	"golang.org/x/time/rate"
 )
 
@@ -27,7 +33,7 @@ func TestNewClient(t *testing.T) {
 
 	t.Run("with custom options", func(t *testing.T) {
 		httpClient := &http.Client{Timeout: 10 * time.Second}
-		retryConfig := RetryConfig{MaxRetries: 5}
+		retryConfig := RetryConfig{MaxRetries: 5, InitialDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second, Multiplier: 2}
 
 		client := NewClient(
 			WithHTTPClient(httpClient),
@@ -42,6 +48,259 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestRetryConfigClamping(t *testing.T) {
+	valid := RetryConfig{MaxRetries: 3, InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		name   string
+		modify func(*RetryConfig)
+		want   func(*RetryConfig)
+		errMsg string
+	}{
+		{
+			name:   "negative retries",
+			modify: func(rc *RetryConfig) { rc.MaxRetries = -1 },
+			want:   func(rc *RetryConfig) { rc.MaxRetries = 0 },
+			errMsg: "max retries must be non-negative, got -1",
+		},
+		{
+			name:   "zero multiplier",
+			modify: func(rc *RetryConfig) { rc.Multiplier = 0 },
+			want:   func(rc *RetryConfig) { rc.Multiplier = 1 },
+			errMsg: "multiplier must be at least 1, got 0",
+		},
+		{
+			name:   "zero initial delay",
+			modify: func(rc *RetryConfig) { rc.InitialDelay = 0 },
+			want:   func(rc *RetryConfig) { rc.InitialDelay = MinRetryDelay },
+			errMsg: "initial delay must be at least 1ms, got 0s",
+		},
+		{
+			name:   "max delay below initial delay",
+			modify: func(rc *RetryConfig) { rc.MaxDelay = 0 },
+			want:   func(rc *RetryConfig) { rc.MaxDelay = rc.InitialDelay },
+			errMsg: "max delay must be at least the initial delay of 100ms, got 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, want := valid, valid
+			tt.modify(&config)
+			tt.want(&want)
+
+			assert.EqualError(t, config.Validate(), tt.errMsg)
+			assert.Equal(t, want, NewClient(WithRetryConfig(config)).retryConfig)
+		})
+	}
+
+	require.NoError(t, valid.Validate())
+	assert.Equal(t, valid, NewClient(WithRetryConfig(valid)).retryConfig)
+}
+
+func TestClientFloodWaitRetry(t *testing.T) {
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "FLOOD_WAIT_1"})
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15", URL: "https://telegra.ph/Test-12-15"}})
+	}))
+	defer server.Close()
+
+	const jitter = 200 * time.Millisecond
+	retryConfig := RetryConfig{MaxRetries: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1, Jitter: jitter}
+	req := &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: NewContentBuilder().AddParagraph("Hello").Build()}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		attempts = nil
+		_, err := NewClient(WithBaseURL(server.URL), WithRetryConfig(retryConfig)).CreatePage(context.Background(), req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		wait, ok := apiErr.FloodWait()
+		assert.True(t, ok)
+		assert.Equal(t, time.Second, wait)
+		assert.Len(t, attempts, 1)
+	})
+
+	t.Run("retried after flood wait plus jitter", func(t *testing.T) {
+		attempts = nil
+		retryConfig.MaxFloodWait = 5 * time.Second
+		page, err := NewClient(WithBaseURL(server.URL), WithRetryConfig(retryConfig)).CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "Test-12-15", page.Path)
+
+		require.Len(t, attempts, 2)
+		wake := attempts[1].Sub(attempts[0])
+		assert.GreaterOrEqual(t, wake, time.Second)
+		assert.Less(t, wake, time.Second+jitter+100*time.Millisecond)
+	})
+
+	t.Run("jitter bounds", func(t *testing.T) {
+		client := NewClient(WithRetryConfig(retryConfig))
+		for i := 0; i < 100; i++ {
+			delay := client.floodWaitDelay(time.Second)
+			assert.GreaterOrEqual(t, delay, time.Second)
+			assert.Less(t, delay, time.Second+jitter)
+		}
+	})
+}
+
+func TestClientRetryDeadlineBudget(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	newClient := func(delay time.Duration) *Client {
+		return NewClient(
+			WithBaseURL(server.URL),
+			WithRetryConfig(RetryConfig{MaxRetries: 3, InitialDelay: delay, MaxDelay: delay, Multiplier: 1}),
+		)
+	}
+
+	t.Run("backoff past the deadline fails fast", func(t *testing.T) {
+		attempts.Store(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := newClient(5*time.Second).GetAccountInfo(ctx, &GetAccountInfoRequest{AccessToken: "test_token"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "received status code 503")
+		assert.Less(t, time.Since(start), 250*time.Millisecond)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("backoff within the deadline is retried", func(t *testing.T) {
+		attempts.Store(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := newClient(10*time.Millisecond).GetAccountInfo(ctx, &GetAccountInfoRequest{AccessToken: "test_token"})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(4), attempts.Load())
+	})
+}
+
+func TestClientRetryableKinds(t *testing.T) {
+	var attempts, failures int
+	var failure string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= failures {
+			json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: failure})
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15", URL: "https://telegra.ph/Test-12-15"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRetryConfig(RetryConfig{
+		MaxRetries:     2,
+		InitialDelay:   time.Millisecond,
+		MaxDelay:       time.Millisecond,
+		Multiplier:     1,
+		RetryableKinds: []ErrorKind{ErrorKindPageSaveFailed},
+	}))
+	req := &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: NewContentBuilder().AddParagraph("Hello").Build()}
+
+	t.Run("transient kind retried", func(t *testing.T) {
+		attempts, failures, failure = 0, 1, "PAGE_SAVE_FAILED"
+		page, err := client.CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "Test-12-15", page.Path)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("validation kind not retried", func(t *testing.T) {
+		attempts, failures, failure = 0, 1, "CONTENT_TOO_BIG"
+		_, err := client.CreatePage(context.Background(), req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindContentTooBig, apiErr.Kind)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("last attempt returned as-is", func(t *testing.T) {
+		attempts, failures, failure = 0, 3, "PAGE_SAVE_FAILED"
+		_, err := client.CreatePage(context.Background(), req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindPageSaveFailed, apiErr.Kind)
+		assert.Equal(t, 3, attempts)
+	})
+}
+
+func TestClientMinTLSVersion(t *testing.T) {
+	minVersion := func(client *Client) uint16 {
+		transport, ok := client.httpClient.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.NotNil(t, transport.TLSClientConfig)
+		return transport.TLSClientConfig.MinVersion
+	}
+
+	t.Run("defaults to TLS 1.2", func(t *testing.T) {
+		assert.Equal(t, uint16(tls.VersionTLS12), minVersion(NewClient()))
+	})
+
+	t.Run("applied to the default transport", func(t *testing.T) {
+		client := NewClient(WithMinTLSVersion(tls.VersionTLS13))
+		assert.Equal(t, uint16(tls.VersionTLS13), minVersion(client))
+
+		// The shared default transport is left unchanged
+		assert.NotSame(t, http.DefaultTransport, client.httpClient.Transport)
+	})
+
+	t.Run("custom HTTP client is not modified", func(t *testing.T) {
+		httpClient := &http.Client{}
+		client := NewClient(WithHTTPClient(httpClient), WithMinTLSVersion(tls.VersionTLS13))
+		assert.Same(t, httpClient, client.httpClient)
+		assert.Nil(t, httpClient.Transport)
+	})
+}
+
+func TestClientResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the response headers until the test is done
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithResponseHeaderTimeout(50*time.Millisecond),
+		WithRetryConfig(RetryConfig{MaxRetries: 0, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}),
+	)
+
+	start := time.Now()
+	_, err := client.GetAccountInfo(context.Background(), &GetAccountInfoRequest{AccessToken: "test_token"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout awaiting response headers")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	t.Run("custom HTTP client is not modified", func(t *testing.T) {
+		httpClient := &http.Client{}
+		client := NewClient(WithHTTPClient(httpClient), WithResponseHeaderTimeout(time.Second))
+		assert.Same(t, httpClient, client.httpClient)
+		assert.Nil(t, httpClient.Transport)
+	})
+}
+
 func TestClientCreateAccount(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "POST", r.Method)
@@ -88,6 +347,37 @@ func TestClientCreateAccount(t *testing.T) {
 	assert.Equal(t, "https://edit.telegra.ph/auth/test-auth-url", account.AuthURL)
 }
 
+func TestClientHeaders(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{ShortName: "Test"}})
+	}))
+	defer server.Close()
+
+	t.Run("defaults", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL))
+
+		_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{ShortName: "Test"})
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", headers.Get("Accept"))
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		client := NewClient(
+			WithBaseURL(server.URL),
+			WithAccept("application/vnd.gateway+json"),
+			WithContentType("application/json; charset=utf-8"),
+		)
+
+		_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{ShortName: "Test"})
+		require.NoError(t, err)
+		assert.Equal(t, "application/vnd.gateway+json", headers.Get("Accept"))
+		assert.Equal(t, "application/json; charset=utf-8", headers.Get("Content-Type"))
+	})
+}
+
 func TestClientCreateAccountValidation(t *testing.T) {
 	client := NewClient()
 
@@ -161,78 +451,138 @@ func TestClientCreatePage(t *testing.T) {
 	assert.True(t, page.CanEdit)
 }
 
-func TestClientGetPage(t *testing.T) {
+func TestClientCreatePageRaw(t *testing.T) {
+	const content = `[{"tag":"p","children":["Hello, ",{"tag":"b","children":["World"]}]}]`
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/getPage", r.URL.Path)
-		assert.Equal(t, "Test-Article-12-15", r.URL.Query().Get("path"))
-		assert.Equal(t, "true", r.URL.Query().Get("return_content"))
+		assert.Equal(t, "/createPage", r.URL.Path)
 
-		resp := APIResponse{
-			Ok: true,
-			Result: Page{
-				Path:        "Test-Article-12-15",
-				URL:         "https://telegra.ph/Test-Article-12-15",
-				Title:       "Test Article",
-				Description: "Test description",
-				Views:       42,
-				Content: []Node{
-					{
-						Tag: "p",
-						Children: []interface{}{
-							Node{Content: "Hello, World!"},
-						},
-					},
-				},
-			},
-		}
+		var body map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.JSONEq(t, `"test-token"`, string(body["access_token"]))
+		assert.JSONEq(t, `"Test Article"`, string(body["title"]))
+		assert.JSONEq(t, `"John Doe"`, string(body["author_name"]))
+		assert.JSONEq(t, `true`, string(body["return_content"]))
+		assert.JSONEq(t, content, string(body["content"]))
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-Article-12-15", Title: "Test Article"}})
 	}))
 	defer server.Close()
 
 	client := NewClient(WithBaseURL(server.URL))
 
-	page, err := client.GetPage(context.Background(), &GetPageRequest{
-		Path:          "Test-Article-12-15",
-		ReturnContent: true,
+	page, err := client.CreatePageRaw(context.Background(), "test-token", "Test Article", json.RawMessage(content),
+		WithPageAuthor("John Doe", ""),
+		WithPageReturnContent(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Test-Article-12-15", page.Path)
+
+	t.Run("invalid content", func(t *testing.T) {
+		for _, raw := range []string{``, `{"tag":"p"}`, `[]`, `[1]`, `[{"tag":"p"}`} {
+			_, err := client.CreatePageRaw(context.Background(), "test-token", "Test Article", json.RawMessage(raw))
+			assert.Error(t, err, raw)
+		}
 	})
 
+	t.Run("invalid fields", func(t *testing.T) {
+		_, err := client.CreatePageRaw(context.Background(), "", "Test Article", json.RawMessage(content))
+		assert.EqualError(t, err, "access_token is required")
+	})
+}
+
+func TestClientCreateLinkListPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/createPage", r.URL.Path)
+
+		var body map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.JSONEq(t, `"My Links"`, string(body["title"]))
+		assert.JSONEq(t, `"John Doe"`, string(body["author_name"]))
+		assert.JSONEq(t, `[{"tag":"ul","children":[
+			{"tag":"li","children":[{"tag":"a","attrs":{"href":"https://example.com/blog"},"children":["Blog"]}]},
+			{"tag":"li","children":[{"tag":"a","attrs":{"href":"https://example.com/shop"},"children":["https://example.com/shop"]}]}
+		]}]`, string(body["content"]))
+
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "My-Links-12-15", Title: "My Links"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	links := []LinkItem{
+		{Text: "Blog", URL: "https://example.com/blog"},
+		{URL: "https://example.com/shop"},
+	}
+
+	page, err := client.CreateLinkListPage(context.Background(), "test-token", "My Links", links, WithPageAuthor("John Doe", ""))
 	require.NoError(t, err)
-	assert.Equal(t, "Test-Article-12-15", page.Path)
-	assert.Equal(t, "Test Article", page.Title)
-	assert.Equal(t, 42, page.Views)
-	assert.Len(t, page.Content, 1)
+	assert.Equal(t, "My-Links-12-15", page.Path)
+
+	t.Run("invalid links", func(t *testing.T) {
+		_, err := client.CreateLinkListPage(context.Background(), "test-token", "My Links", nil)
+		assert.EqualError(t, err, "at least one link is required")
+
+		_, err = client.CreateLinkListPage(context.Background(), "test-token", "My Links", []LinkItem{{Text: "Blog"}})
+		assert.EqualError(t, err, "links[0]: url is required")
+	})
 }
 
-func TestClientGetPageList(t *testing.T) {
+func TestClientPageValidation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "/getPageList", r.URL.Path)
+		// A misbehaving server that leaves out the page path
+		fmt.Fprint(w, `{"ok":true,"result":{"url":"https://telegra.ph/","title":"Test Article"}}`)
+	}))
+	defer server.Close()
 
-		var req GetPageListRequest
-		err := json.NewDecoder(r.Body).Decode(&req)
+	req := &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test Article",
+		Content:     NewContentBuilder().AddParagraph("Hello").Build(),
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		page, err := NewClient(WithBaseURL(server.URL)).CreatePage(context.Background(), req)
 		require.NoError(t, err)
+		assert.Empty(t, page.Path)
+	})
 
-		assert.Equal(t, "test-token", req.AccessToken)
-		assert.Equal(t, 0, req.Offset)
-		assert.Equal(t, 10, req.Limit)
+	t.Run("enabled", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL), WithPageValidation())
 
-		resp := APIResponse{
-			Ok: true,
-			Result: PageList{
-				TotalCount: 1,
-				Pages: []Page{
-					{
-						Path:        "Test-Article-12-15",
-						URL:         "https://telegra.ph/Test-Article-12-15",
-						Title:       "Test Article",
-						Description: "Test description",
-						Views:       42,
-					},
-				},
-			},
+		_, err := client.CreatePage(context.Background(), req)
+		assert.ErrorIs(t, err, ErrMalformedPage)
+		assert.EqualError(t, err, "malformed page: path is missing")
+
+		_, err = client.EditPage(context.Background(), &EditPageRequest{
+			AccessToken: "test-token",
+			Path:        "Test-Article-12-15",
+			Title:       "Test Article",
+			Content:     req.Content,
+		})
+		assert.ErrorIs(t, err, ErrMalformedPage)
+	})
+
+	t.Run("page validate", func(t *testing.T) {
+		assert.NoError(t, Page{Path: "Test-Article-12-15", URL: "https://telegra.ph/Test-Article-12-15"}.Validate())
+		assert.EqualError(t, Page{Path: "Test-Article-12-15"}.Validate(), "malformed page: url is missing")
+	})
+}
+
+func TestClientDefaultAuthorFromAccount(t *testing.T) {
+	accountInfoCalls := 0
+	var lastPage CreatePageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp APIResponse
+		switch r.URL.Path {
+		case "/getAccountInfo":
+			accountInfoCalls++
+			resp = APIResponse{Ok: true, Result: Account{
+				AuthorName: "Account Author",
+				AuthorURL:  "https://example.com/account",
+			}}
+		case "/createPage":
+			err := json.NewDecoder(r.Body).Decode(&lastPage)
+			require.NoError(t, err)
+			resp = APIResponse{Ok: true, Result: Page{Path: "Test", AuthorName: lastPage.AuthorName, AuthorURL: lastPage.AuthorURL}}
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -241,37 +591,66 @@ func TestClientGetPageList(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(WithBaseURL(server.URL))
+	require.NoError(t, client.SetDefaultAuthorFromAccount(context.Background(), "test-token"))
 
-	pageList, err := client.GetPageList(context.Background(), &GetPageListRequest{
-		AccessToken: "test-token",
-		Offset:      0,
-		Limit:       10,
+	content := NewContentBuilder().AddParagraph("Hello").Build()
+
+	t.Run("cached author is applied", func(t *testing.T) {
+		req := &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: content}
+		_, err := client.CreatePage(context.Background(), req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Account Author", lastPage.AuthorName)
+		assert.Equal(t, "https://example.com/account", lastPage.AuthorURL)
+		assert.Empty(t, req.AuthorName, "caller's request should not be modified")
 	})
 
-	require.NoError(t, err)
-	assert.Equal(t, 1, pageList.TotalCount)
-	assert.Len(t, pageList.Pages, 1)
-	assert.Equal(t, "Test-Article-12-15", pageList.Pages[0].Path)
+	t.Run("explicit values override", func(t *testing.T) {
+		_, err := client.CreatePage(context.Background(), &CreatePageRequest{
+			AccessToken: "test-token",
+			Title:       "Test",
+			AuthorName:  "Guest Author",
+			Content:     content,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Guest Author", lastPage.AuthorName)
+		assert.Equal(t, "https://example.com/account", lastPage.AuthorURL)
+	})
+
+	assert.Equal(t, 1, accountInfoCalls)
 }
 
-func TestClientGetViews(t *testing.T) {
+func TestClientEditOrCreatePage(t *testing.T) {
+	var calls []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "/getViews", r.URL.Path)
+		calls = append(calls, r.URL.Path)
 
-		var req GetViewsRequest
-		err := json.NewDecoder(r.Body).Decode(&req)
-		require.NoError(t, err)
+		var resp APIResponse
+		switch r.URL.Path {
+		case "/editPage":
+			var req EditPageRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
 
-		assert.Equal(t, "Test-Article-12-15", req.Path)
-		assert.Equal(t, 2023, req.Year)
-		assert.Equal(t, 12, req.Month)
+			assert.Equal(t, "test-token", req.AccessToken)
+			assert.Equal(t, "Docs", req.Title)
+			assert.Equal(t, "CI", req.AuthorName)
+			assert.True(t, req.ReturnContent)
+			assert.Len(t, req.Content, 1)
 
-		resp := APIResponse{
-			Ok: true,
-			Result: PageViews{
-				Views: 100,
-			},
+			if req.Path == "Existing-12-15" {
+				resp = APIResponse{Ok: true, Result: Page{Path: req.Path, Title: req.Title}}
+			} else {
+				resp = APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"}
+			}
+		case "/createPage":
+			var req CreatePageRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			assert.Equal(t, "Docs", req.Title)
+			resp = APIResponse{Ok: true, Result: Page{Path: "Docs-12-16", Title: req.Title}}
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -280,111 +659,1333 @@ func TestClientGetViews(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(WithBaseURL(server.URL))
+	req := &CreatePageRequest{
+		AccessToken:   "test-token",
+		Title:         "Docs",
+		AuthorName:    "CI",
+		Content:       NewContentBuilder().AddParagraph("Hello").Build(),
+		ReturnContent: true,
+	}
 
-	views, err := client.GetViews(context.Background(), &GetViewsRequest{
-		Path:  "Test-Article-12-15",
-		Year:  2023,
-		Month: 12,
+	t.Run("edits existing page", func(t *testing.T) {
+		calls = nil
+		page, err := client.EditOrCreatePage(context.Background(), req, "Existing-12-15")
+		require.NoError(t, err)
+		assert.Equal(t, "Existing-12-15", page.Path)
+		assert.Equal(t, []string{"/editPage"}, calls)
 	})
 
-	require.NoError(t, err)
-	assert.Equal(t, 100, views.Views)
+	t.Run("creates missing page", func(t *testing.T) {
+		calls = nil
+		page, err := client.EditOrCreatePage(context.Background(), req, "Missing-12-15")
+		require.NoError(t, err)
+		assert.Equal(t, "Docs-12-16", page.Path)
+		assert.Equal(t, []string{"/editPage", "/createPage"}, calls)
+	})
 }
 
-func TestClientErrorHandling(t *testing.T) {
+func TestContentBuilderByteSizeMatchesRequest(t *testing.T) {
+	var sent json.RawMessage
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusBadRequest)
-		resp := APIError{
-			Code:        400,
-			Description: "Bad Request",
+		var req struct {
+			Content json.RawMessage `json:"content"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		sent = req.Content
+
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	builder := NewContentBuilder().
+		AddHeading("Über <title>", 3).
+		AddParagraph("Hello, \"World\" & friends — ünïcödé").
+		AddLink("Link", "https://example.com/?a=1&b=2").
+		AddImage("/file/image.jpg")
+
+	_, err := client.CreatePage(context.Background(), &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test",
+		Content:     builder.Build(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, len(sent), builder.ByteSize())
+}
+
+func TestClientWithReturnContent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			got = append(got, r.URL.Query().Get("return_content"))
+		} else {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			value, _ := body["return_content"].(bool)
+			got = append(got, strconv.FormatBool(value))
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15", URL: "https://telegra.ph/Test-12-15"}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	content := NewContentBuilder().AddParagraph("Hello").Build()
+	calls := func(client *Client, requested bool) {
+		createReq := &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: content, ReturnContent: requested}
+		_, err := client.CreatePage(ctx, createReq)
+		require.NoError(t, err)
+		assert.Equal(t, requested, createReq.ReturnContent, "request must not be modified")
+
+		_, err = client.EditPage(ctx, &EditPageRequest{AccessToken: "test-token", Path: "Test-12-15", Title: "Test", Content: content, ReturnContent: requested})
+		require.NoError(t, err)
+		_, err = client.GetPage(ctx, &GetPageRequest{Path: "Test-12-15", ReturnContent: requested})
+		require.NoError(t, err)
+	}
+
+	t.Run("request field without option", func(t *testing.T) {
+		got = nil
+		client := NewClient(WithBaseURL(server.URL))
+		calls(client, true)
+		calls(client, false)
+		assert.Equal(t, []string{"true", "true", "true", "false", "false", ""}, got)
+	})
+
+	t.Run("option true overrides requests", func(t *testing.T) {
+		got = nil
+		calls(NewClient(WithBaseURL(server.URL), WithReturnContent(true)), false)
+		assert.Equal(t, []string{"true", "true", "true"}, got)
+	})
+
+	t.Run("option false overrides requests", func(t *testing.T) {
+		got = nil
+		client := NewClient(WithBaseURL(server.URL), WithReturnContent(false))
+		calls(client, true)
+		assert.Equal(t, []string{"false", "false", ""}, got)
+
+		// Helpers that need the content still request it
+		got = nil
+		_, err := client.ContentChanged(ctx, "test-token", "Test-12-15", content)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"true"}, got)
+	})
+}
+
+func TestClientGetPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/getPage", r.URL.Path)
+		assert.Equal(t, "Test-Article-12-15", r.URL.Query().Get("path"))
+		assert.Equal(t, "true", r.URL.Query().Get("return_content"))
+
+		resp := APIResponse{
+			Ok: true,
+			Result: Page{
+				Path:        "Test-Article-12-15",
+				URL:         "https://telegra.ph/Test-Article-12-15",
+				Title:       "Test Article",
+				Description: "Test description",
+				Views:       42,
+				Content: []Node{
+					{
+						Tag: "p",
+						Children: []interface{}{
+							Node{Content: "Hello, World!"},
+						},
+					},
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	page, err := client.GetPage(context.Background(), &GetPageRequest{
+		Path:          "Test-Article-12-15",
+		ReturnContent: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Test-Article-12-15", page.Path)
+	assert.Equal(t, "Test Article", page.Title)
+	assert.Equal(t, 42, page.Views)
+	assert.Len(t, page.Content, 1)
+}
+
+func TestClientContentChanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/getPage", r.URL.Path)
+		assert.Empty(t, r.URL.RawQuery, "access token must not be sent in the URL")
+		var req canEditRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, canEditRequest{AccessToken: "test-token", Path: "Test-12-15", ReturnContent: true}, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{"path":"Test-12-15","content":[`+
+			`{"tag":"p","children":["Hello, ",{"tag":"b","children":["World"]},"!"]},`+
+			`{"tag":"p","children":["Second  paragraph"]}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	unchanged := []Node{
+		{Tag: "p", Children: []interface{}{
+			Node{Content: "Hello, "},
+			Node{Tag: "b", Children: []interface{}{Node{Content: "World"}}},
+			Node{Content: "!"},
+		}},
+		NewContentBuilder().AddParagraph("Second  paragraph").Build()[0],
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		changed, err := client.ContentChanged(ctx, "test-token", "Test-12-15", unchanged)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		local := NewContentBuilder().AddParagraph("Hello, World!").AddParagraph("Second  paragraph").Build()
+		changed, err := client.ContentChanged(ctx, "test-token", "Test-12-15", local)
+		require.NoError(t, err)
+		assert.True(t, changed)
+	})
+
+	t.Run("whitespace", func(t *testing.T) {
+		local := append([]Node{}, unchanged[0], NewContentBuilder().AddParagraph("Second paragraph ").Build()[0])
+
+		changed, err := client.ContentChanged(ctx, "test-token", "Test-12-15", local)
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		changed, err = client.ContentChanged(ctx, "test-token", "Test-12-15", local, IgnoreWhitespace())
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("missing access token", func(t *testing.T) {
+		_, err := client.ContentChanged(ctx, "", "Test-12-15", unchanged)
+		assert.Error(t, err)
+	})
+}
+
+func TestClientEmptyErrorResponse(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	req := &GetPageRequest{Path: "Test-12-15"}
+
+	t.Run("default message with body", func(t *testing.T) {
+		body = `{"ok":false,"shim":"upstream timeout"}`
+		_, err := NewClient(WithBaseURL(server.URL)).GetPage(ctx, req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, `API returned ok: false (body: {"ok":false,"shim":"upstream timeout"})`, apiErr.Description)
+	})
+
+	t.Run("custom message", func(t *testing.T) {
+		body = `{"ok":false}`
+		_, err := NewClient(WithBaseURL(server.URL), WithEmptyErrorMessage("shim rejected the request")).GetPage(ctx, req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, `shim rejected the request (body: {"ok":false})`, apiErr.Description)
+	})
+
+	t.Run("description field", func(t *testing.T) {
+		body = `{"ok":false,"description":"PAGE_NOT_FOUND"}`
+		_, err := NewClient(WithBaseURL(server.URL)).GetPage(ctx, req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "PAGE_NOT_FOUND", apiErr.Description)
+		assert.Equal(t, ErrorKindPageNotFound, apiErr.Kind)
+	})
+
+	t.Run("long body is truncated", func(t *testing.T) {
+		body = `{"ok":false,"detail":"` + strings.Repeat("x", 1000) + `"}`
+		_, err := NewClient(WithBaseURL(server.URL)).GetPage(ctx, req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Len(t, apiErr.Description, len("API returned ok: false (body: ")+maxErrorBodySnippet+len("...)"))
+	})
+}
+
+func TestClientFallbackBaseURL(t *testing.T) {
+	fallbackCalls := 0
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackCalls++
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15", URL: "https://telegra.ph/Test-12-15"}})
+	}))
+	defer fallback.Close()
+
+	// A primary that is down refuses connections
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	retryConfig := WithRetryConfig(RetryConfig{MaxRetries: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1})
+
+	t.Run("primary down", func(t *testing.T) {
+		fallbackCalls = 0
+		client := NewClient(WithBaseURL(down.URL), WithFallbackBaseURL(fallback.URL), retryConfig)
+
+		page, err := client.GetPage(context.Background(), &GetPageRequest{Path: "Test-12-15"})
+		require.NoError(t, err)
+		assert.Equal(t, "Test-12-15", page.Path)
+
+		_, err = client.CreatePage(context.Background(), &CreatePageRequest{
+			AccessToken: "test-token",
+			Title:       "Test",
+			Content:     NewContentBuilder().AddParagraph("Hello").Build(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, fallbackCalls)
+	})
+
+	t.Run("API errors do not fall back", func(t *testing.T) {
+		fallbackCalls = 0
+		primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"})
+		}))
+		defer primary.Close()
+
+		client := NewClient(WithBaseURL(primary.URL), WithFallbackBaseURL(fallback.URL), retryConfig)
+		_, err := client.GetPage(context.Background(), &GetPageRequest{Path: "Test-12-15"})
+		assert.Error(t, err)
+		assert.Equal(t, 0, fallbackCalls)
+	})
+
+	t.Run("fallback down", func(t *testing.T) {
+		client := NewClient(WithBaseURL(down.URL), WithFallbackBaseURL(down.URL), retryConfig)
+		_, err := client.GetPage(context.Background(), &GetPageRequest{Path: "Test-12-15"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fallback failed")
+	})
+}
+
+func TestClientRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/createPage", func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		http.Redirect(w, r, "/v2/createPage", status)
+	})
+	mux.HandleFunc("/v2/createPage", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		var req CreatePageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Redirected", req.Title)
+		require.Len(t, req.Content, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Redirected-12-15", URL: "https://telegra.ph/Redirected-12-15"}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	req := &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Redirected",
+		Content:     NewContentBuilder().AddParagraph("Body").Build(),
+	}
+
+	for _, status := range []int{http.StatusTemporaryRedirect, http.StatusPermanentRedirect} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			rewrite := WithURLRewriter(func(method, url string) string {
+				return url + "?status=" + strconv.Itoa(status)
+			})
+			page, err := NewClient(WithBaseURL(server.URL), rewrite).CreatePage(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, "Redirected-12-15", page.Path)
+		})
+	}
+
+	t.Run("not followed", func(t *testing.T) {
+		client := NewClient(
+			WithBaseURL(server.URL),
+			WithFollowRedirects(false),
+			WithURLRewriter(func(method, url string) string { return url + "?status=307" }),
+		)
+		_, err := client.CreatePage(context.Background(), req)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTemporaryRedirect, apiErr.Code)
+	})
+}
+
+func TestClientNilRequests(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"CreateAccount", func() error { _, err := client.CreateAccount(ctx, nil); return err }},
+		{"EditAccountInfo", func() error { _, err := client.EditAccountInfo(ctx, nil); return err }},
+		{"GetAccountInfo", func() error { _, err := client.GetAccountInfo(ctx, nil); return err }},
+		{"CreatePage", func() error { _, err := client.CreatePage(ctx, nil); return err }},
+		{"EditPage", func() error { _, err := client.EditPage(ctx, nil); return err }},
+		{"EditOrCreatePage", func() error { _, err := client.EditOrCreatePage(ctx, nil, "Page-12-15"); return err }},
+		{"EditOrCreatePage without path", func() error { _, err := client.EditOrCreatePage(ctx, nil, ""); return err }},
+		{"GetPage", func() error { _, err := client.GetPage(ctx, nil); return err }},
+		{"GetPageList", func() error { _, err := client.GetPageList(ctx, nil); return err }},
+		{"GetViews", func() error { _, err := client.GetViews(ctx, nil); return err }},
+		{"GetAllPages", func() error { _, err := client.GetAllPages(ctx, nil); return err }},
+		{"NewPageIterator", func() error {
+			it := client.NewPageIterator(nil)
+			assert.False(t, it.Next(ctx))
+			return it.Err()
+		}},
+		{"Bootstrap account", func() error {
+			_, _, err := client.Bootstrap(ctx, nil, &CreatePageRequest{})
+			return err
+		}},
+		{"Bootstrap first page", func() error {
+			_, _, err := client.Bootstrap(ctx, &CreateAccountRequest{ShortName: "Test"}, nil)
+			return err
+		}},
+		{"CreateAccountIfAbsent", func() error {
+			_, _, err := client.NewAccountManager().CreateAccountIfAbsent(ctx, nil)
+			return err
+		}},
+		{"CreatePages", func() error { _, errs := client.CreatePages(ctx, []*CreatePageRequest{nil}, 1); return errs[0] }},
+		{"GetPages", func() error { _, errs := client.GetPages(ctx, []*GetPageRequest{nil}, 1); return errs[0] }},
+		{"GetViewsBatch", func() error { _, errs := client.GetViewsBatch(ctx, []*GetViewsRequest{nil}, 1); return errs[0] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), ErrNilRequest)
+		})
+	}
+}
+
+func TestClientCanEditPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/getPage", r.URL.Path)
+		assert.Empty(t, r.URL.RawQuery, "the token must not be sent in the URL")
+
+		var req canEditRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var resp APIResponse
+		switch req.Path {
+		case "Own-Page-12-15":
+			resp = APIResponse{Ok: true, Result: Page{Path: req.Path, CanEdit: req.AccessToken == "owner-token"}}
+		default:
+			resp = APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	t.Run("editable", func(t *testing.T) {
+		ok, err := client.CanEditPage(ctx, "owner-token", "Own-Page-12-15")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("not editable", func(t *testing.T) {
+		ok, err := client.CanEditPage(ctx, "other-token", "Own-Page-12-15")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("missing page", func(t *testing.T) {
+		ok, err := client.CanEditPage(ctx, "owner-token", "Missing-12-15")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindPageNotFound, apiErr.Kind)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := client.CanEditPage(ctx, "", "Own-Page-12-15")
+		assert.EqualError(t, err, "access_token is required")
+		_, err = client.CanEditPage(ctx, "owner-token", "/Own-Page-12-15")
+		assert.Error(t, err)
+	})
+}
+
+func TestClientEditableContent(t *testing.T) {
+	var edited EditPageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Empty(t, r.URL.RawQuery, "the token must not be sent in the URL")
+
+		var resp APIResponse
+		switch r.URL.Path {
+		case "/getPage":
+			var req canEditRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.ReturnContent)
+
+			if req.Path != "Own-Page-12-15" {
+				resp = APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"}
+				break
+			}
+			resp = APIResponse{Ok: true, Result: Page{
+				Path:    req.Path,
+				Title:   "Own Page",
+				CanEdit: req.AccessToken == "owner-token",
+				Content: []Node{{Tag: "p", Children: []interface{}{"Hello, ", Node{Tag: "b", Children: []interface{}{"world"}}}}},
+			}}
+		case "/editPage":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edited))
+			resp = APIResponse{Ok: true, Result: Page{Path: "Own-Page-12-15", Title: edited.Title}}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	t.Run("round trip", func(t *testing.T) {
+		cb, page, err := client.EditableContent(ctx, "owner-token", "Own-Page-12-15")
+		require.NoError(t, err)
+		assert.Equal(t, "Own Page", page.Title)
+		assert.True(t, page.CanEdit)
+
+		// The builder holds a copy of the page content with normalized children
+		content := cb.AddParagraph("Updated").Build()
+		require.Len(t, content, 2)
+		assert.Equal(t, Node{Tag: "b", Children: []interface{}{"world"}}, content[0].Children[1])
+		assert.Len(t, page.Content, 1)
+
+		_, err = client.EditPage(ctx, &EditPageRequest{
+			AccessToken: "owner-token",
+			Path:        page.Path,
+			Title:       page.Title,
+			Content:     content,
+		})
+		require.NoError(t, err)
+		assert.True(t, NodesEqual(content, edited.Content))
+	})
+
+	t.Run("other account", func(t *testing.T) {
+		cb, page, err := client.EditableContent(ctx, "other-token", "Own-Page-12-15")
+		require.NoError(t, err)
+		assert.False(t, page.CanEdit)
+		assert.NotNil(t, cb)
+	})
+
+	t.Run("missing page", func(t *testing.T) {
+		cb, page, err := client.EditableContent(ctx, "owner-token", "Missing-12-15")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindPageNotFound, apiErr.Kind)
+		assert.Nil(t, cb)
+		assert.Nil(t, page)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, _, err := client.EditableContent(ctx, "", "Own-Page-12-15")
+		assert.EqualError(t, err, "access_token is required")
+		_, _, err = client.EditableContent(ctx, "owner-token", "/Own-Page-12-15")
+		assert.Error(t, err)
+	})
+}
+
+func TestClientPageExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getPage", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("return_content"))
+
+		var resp APIResponse
+		switch r.URL.Query().Get("path") {
+		case "Existing-Page-12-15":
+			resp = APIResponse{Ok: true, Result: Page{Path: "Existing-Page-12-15"}}
+		case "Missing-Page-12-15":
+			resp = APIResponse{Ok: false, Error: "PAGE_NOT_FOUND"}
+		default:
+			resp = APIResponse{Ok: false, Error: "PAGE_PATH_INVALID"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	t.Run("existing page", func(t *testing.T) {
+		exists, err := client.PageExists(context.Background(), "Existing-Page-12-15")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("missing page", func(t *testing.T) {
+		exists, err := client.PageExists(context.Background(), "Missing-Page-12-15")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("other errors are propagated", func(t *testing.T) {
+		exists, err := client.PageExists(context.Background(), "Rejected-Page-12-15")
+		require.Error(t, err)
+		assert.False(t, exists)
+		assert.Contains(t, err.Error(), "PAGE_PATH_INVALID")
+	})
+
+	t.Run("malformed path rejected locally", func(t *testing.T) {
+		exists, err := client.PageExists(context.Background(), "???")
+		assert.EqualError(t, err, "path contains invalid character '?'")
+		assert.False(t, exists)
+	})
+}
+
+func TestClientGetPageList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/getPageList", r.URL.Path)
+
+		var req GetPageListRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-token", req.AccessToken)
+		assert.Equal(t, 0, req.Offset)
+		assert.Equal(t, 10, req.Limit)
+
+		resp := APIResponse{
+			Ok: true,
+			Result: PageList{
+				TotalCount: 1,
+				Pages: []Page{
+					{
+						Path:        "Test-Article-12-15",
+						URL:         "https://telegra.ph/Test-Article-12-15",
+						Title:       "Test Article",
+						Description: "Test description",
+						Views:       42,
+					},
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	pageList, err := client.GetPageList(context.Background(), &GetPageListRequest{
+		AccessToken: "test-token",
+		Offset:      0,
+		Limit:       10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, pageList.TotalCount)
+	assert.Len(t, pageList.Pages, 1)
+	assert.Equal(t, "Test-Article-12-15", pageList.Pages[0].Path)
+}
+
+func TestClientDefaultPageListLimit(t *testing.T) {
+	var limits []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		limits = append(limits, body["limit"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageList{}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("default applied", func(t *testing.T) {
+		limits = nil
+		req := &GetPageListRequest{AccessToken: "test-token"}
+		_, err := NewClient(WithBaseURL(server.URL), WithDefaultPageListLimit(200)).GetPageList(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{float64(200)}, limits)
+		assert.Zero(t, req.Limit, "the caller's request is not modified")
+	})
+
+	t.Run("explicit limit overrides", func(t *testing.T) {
+		limits = nil
+		_, err := NewClient(WithBaseURL(server.URL), WithDefaultPageListLimit(200)).GetPageList(ctx, &GetPageListRequest{AccessToken: "test-token", Limit: 5})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{float64(5)}, limits)
+	})
+
+	t.Run("API default without option", func(t *testing.T) {
+		limits = nil
+		_, err := NewClient(WithBaseURL(server.URL)).GetPageList(ctx, &GetPageListRequest{AccessToken: "test-token"})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{nil}, limits)
+	})
+
+	t.Run("clamped", func(t *testing.T) {
+		assert.Equal(t, MaxPageListLimit, NewClient(WithDefaultPageListLimit(500)).defaultPageListLimit)
+		assert.Zero(t, NewClient(WithDefaultPageListLimit(-1)).defaultPageListLimit)
+	})
+}
+
+func TestClientGetViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/getViews", r.URL.Path)
+
+		var req GetViewsRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Test-Article-12-15", req.Path)
+		assert.Equal(t, 2023, req.Year)
+		assert.Equal(t, 12, req.Month)
+
+		resp := APIResponse{
+			Ok: true,
+			Result: PageViews{
+				Views: 100,
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	views, err := client.GetViews(context.Background(), &GetViewsRequest{
+		Path:  "Test-Article-12-15",
+		Year:  2023,
+		Month: 12,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 100, views.Views)
+}
+
+func TestClientGetViewsToday(t *testing.T) {
+	var req GetViewsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getViews", r.URL.Path)
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 7}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	before := time.Now().UTC()
+	views, err := client.GetViewsToday(context.Background(), "Test-Article-12-15")
+	after := time.Now().UTC()
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, views.Views)
+	assert.Equal(t, "Test-Article-12-15", req.Path)
+	assert.Zero(t, req.Hour)
+
+	// Accept either side of a midnight rollover during the call
+	date := time.Date(req.Year, time.Month(req.Month), req.Day, 0, 0, 0, 0, time.UTC)
+	assert.True(t, date.Equal(before.Truncate(24*time.Hour)) || date.Equal(after.Truncate(24*time.Hour)))
+}
+
+func TestClientErrorHandling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		resp := APIError{
+			Code:        400,
+			Description: "Bad Request",
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
+		ShortName: "Test",
+	})
+
+	require.Error(t, err)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 400, apiErr.Code)
+	assert.Equal(t, "Bad Request", apiErr.Description)
+	assert.Equal(t, ErrorKindUnknown, apiErr.Kind)
+}
+
+func TestClientErrorKind(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "TITLE_REQUIRED"})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	_, err := client.GetPage(context.Background(), &GetPageRequest{Path: "Test-Article-12-15"})
+
+	require.Error(t, err)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "TITLE_REQUIRED", apiErr.Description)
+	assert.Equal(t, ErrorKindTitleRequired, apiErr.Kind)
+}
+
+func TestClientRetryLogic(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		resp := APIResponse{
+			Ok: true,
+			Result: Account{
+				ShortName:   "Test",
+				AccessToken: "test-token",
+			},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(RetryConfig{
+			MaxRetries:         3,
+			InitialDelay:       1 * time.Millisecond,
+			MaxDelay:           10 * time.Millisecond,
+			Multiplier:         2.0,
+			RetryNonIdempotent: true,
+		}),
+	)
+
+	account, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
+		ShortName: "Test",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Test", account.ShortName)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestClientRetryBudget(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(RetryConfig{
+			MaxRetries:   3,
+			InitialDelay: 1 * time.Millisecond,
+			MaxDelay:     10 * time.Millisecond,
+			Multiplier:   2.0,
+		}),
+		WithRetryBudget(0.001, 2),
+	)
+
+	// The first call spends the whole budget on its first two retries
+	_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Equal(t, 3, attempts)
+
+	// Later calls fail fast after a single attempt
+	_, err = client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Contains(t, err.Error(), "received status code 500")
+	assert.Equal(t, 4, attempts)
+}
+
+func TestClientURLRewriter(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if len(paths) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-Article-12-15"}})
+	}))
+	defer server.Close()
+
+	var methods []string
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(RetryConfig{MaxRetries: 1, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}),
+		WithURLRewriter(func(method, url string) string {
+			methods = append(methods, method)
+			return strings.Replace(url, "/createPage", "/v2/createPage", 1)
+		}),
+	)
+
+	_, err := client.CreatePage(context.Background(), &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test Article",
+		Content:     []Node{{Tag: "p", Children: []interface{}{"Hello"}}},
+	})
+	require.NoError(t, err)
+
+	// The rewriter is applied to the retry as well
+	assert.Equal(t, []string{"/v2/createPage", "/v2/createPage"}, paths)
+	assert.Equal(t, []string{"POST", "POST"}, methods)
+}
+
+func TestClientRetryIdempotentOnly(t *testing.T) {
+	attempts := map[string]int{}
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.URL.Path]++
+		if r.URL.Path == "/createPage" {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	retryConfig := RetryConfig{
+		MaxRetries:   2,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+	createReq := &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test",
+		Content:     NewContentBuilder().AddParagraph("Hello").Build(),
+	}
+
+	t.Run("default retries reads only", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL), WithRetryConfig(retryConfig))
+
+		_, err := client.CreatePage(context.Background(), createReq)
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts["/createPage"])
+
+		_, err = client.GetPage(context.Background(), &GetPageRequest{Path: "Test"})
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts["/getPage"])
+	})
+
+	t.Run("writes retried when enabled", func(t *testing.T) {
+		attempts = map[string]int{}
+		bodies = nil
+
+		config := retryConfig
+		config.RetryNonIdempotent = true
+		client := NewClient(WithBaseURL(server.URL), WithRetryConfig(config))
+
+		_, err := client.CreatePage(context.Background(), createReq)
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts["/createPage"])
+
+		// Every attempt must carry the full request body
+		require.Len(t, bodies, 3)
+		assert.Equal(t, bodies[0], bodies[2])
+		assert.Contains(t, bodies[2], "test-token")
+	})
+
+	t.Run("writes retried on 429", func(t *testing.T) {
+		count := 0
+		limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count++
+			if count == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test"}})
+		}))
+		defer limited.Close()
+
+		client := NewClient(WithBaseURL(limited.URL), WithRetryConfig(retryConfig))
+
+		_, err := client.CreatePage(context.Background(), createReq)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+func TestClientRateLimiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := APIResponse{
+			Ok: true,
+			Result: Account{
+				ShortName:   "Test",
+				AccessToken: "test-token",
+			},
 		}
 		json.NewEncoder(w).Encode(resp)
 	}))
 	defer server.Close()
 
-	client := NewClient(WithBaseURL(server.URL))
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1)), // 1 request per second
+	)
+
+	start := time.Now()
+
+	// Make two requests
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
+			ShortName: fmt.Sprintf("Test%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	duration := time.Since(start)
+	// Should take at least 1 second due to rate limiting
+	assert.True(t, duration >= 1*time.Second)
+}
+
+func TestClientSharedRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	// One call every 100ms in total, shared by both clients
+	limiter := rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+	clients := []*Client{
+		NewClient(WithBaseURL(server.URL), WithSharedRateLimiter(limiter)),
+		NewClient(WithBaseURL(server.URL), WithSharedRateLimiter(limiter)),
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-12-15"})
+				assert.NoError(t, err)
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	// Six calls through one limiter need five intervals; each client alone would need two
+	assert.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+
+	// A nil limiter keeps the default one
+	client := NewClient(WithSharedRateLimiter(nil))
+	assert.NotNil(t, client.rateLimiter)
+}
+
+func TestClientMetricsHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	var metrics []RequestMetrics
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1)), // 1 request per second
+		WithMetricsHook(func(ctx context.Context, m RequestMetrics) {
+			metrics = append(metrics, m)
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "getViews", metrics[0].Endpoint)
+	assert.Equal(t, http.StatusOK, metrics[0].StatusCode)
+	assert.NoError(t, metrics[0].Err)
+	assert.Less(t, metrics[0].RateLimitWait, 100*time.Millisecond)
+
+	// The second call is throttled for roughly one second
+	assert.InDelta(t, float64(time.Second), float64(metrics[1].RateLimitWait), float64(200*time.Millisecond))
+	assert.GreaterOrEqual(t, metrics[1].Duration, metrics[1].RateLimitWait)
+}
+
+func TestClientMetricsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	var metrics []RequestMetrics
+	failures := 0
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}),
+		WithFaultInjector(func(endpoint string) error {
+			if failures > 0 {
+				failures--
+				return InjectStatus(http.StatusServiceUnavailable)
+			}
+			return nil
+		}),
+		WithMetricsHook(func(ctx context.Context, m RequestMetrics) {
+			metrics = append(metrics, m)
+		}),
+	)
 
-	_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
-		ShortName: "Test",
-	})
+	for _, simulated := range []int{0, 2} {
+		failures = simulated
+		_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+		require.NoError(t, err)
+	}
 
+	failures = 10
+	_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
 	require.Error(t, err)
-	var apiErr *APIError
-	assert.ErrorAs(t, err, &apiErr)
-	assert.Equal(t, 400, apiErr.Code)
-	assert.Equal(t, "Bad Request", apiErr.Description)
+
+	require.Len(t, metrics, 3)
+	assert.Equal(t, 0, metrics[0].Retries)
+	assert.Equal(t, 2, metrics[1].Retries)
+	assert.Equal(t, 3, metrics[2].Retries)
 }
 
-func TestClientRetryLogic(t *testing.T) {
-	attempts := 0
+func TestClientRequestHookAttempts(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		attempts++
-		if attempts < 3 {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		resp := APIResponse{
-			Ok: true,
-			Result: Account{
-				ShortName:   "Test",
-				AccessToken: "test-token",
-			},
-		}
-		json.NewEncoder(w).Encode(resp)
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
 	}))
 	defer server.Close()
 
+	var hookAttempts, transportAttempts []int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		transportAttempts = append(transportAttempts, AttemptFromContext(req.Context()))
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	failures := 0
 	client := NewClient(
 		WithBaseURL(server.URL),
-		WithRetryConfig(RetryConfig{
-			MaxRetries:   3,
-			InitialDelay: 1 * time.Millisecond,
-			MaxDelay:     10 * time.Millisecond,
-			Multiplier:   2.0,
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRetryConfig(RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}),
+		WithFaultInjector(func(endpoint string) error {
+			if failures > 0 {
+				failures--
+				return InjectStatus(http.StatusServiceUnavailable)
+			}
+			return nil
+		}),
+		WithRequestHook(func(req *http.Request, attempt int) {
+			assert.Equal(t, "/getViews", req.URL.Path)
+			hookAttempts = append(hookAttempts, attempt)
 		}),
 	)
 
-	account, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
-		ShortName: "Test",
-	})
+	failures = 2
+	_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, hookAttempts)
+	// Injected faults never reach the transport
+	assert.Equal(t, []int{3}, transportAttempts)
 
+	hookAttempts = nil
+	_, err = client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
 	require.NoError(t, err)
-	assert.Equal(t, "Test", account.ShortName)
-	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []int{1}, hookAttempts)
+
+	assert.Zero(t, AttemptFromContext(context.Background()))
 }
 
-func TestClientRateLimiting(t *testing.T) {
+func TestEndpointName(t *testing.T) {
+	assert.Equal(t, "createPage", endpointName("/createPage"))
+	assert.Equal(t, "getPage", endpointName("/getPage?path=Test&return_content=true"))
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientEndpointTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := APIResponse{
-			Ok: true,
-			Result: Account{
-				ShortName:   "Test",
-				AccessToken: "test-token",
-			},
-		}
-		json.NewEncoder(w).Encode(resp)
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test"}})
 	}))
 	defer server.Close()
 
+	deadlines := map[string]time.Duration{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if deadline, ok := req.Context().Deadline(); ok {
+			deadlines[req.URL.Path] = time.Until(deadline)
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
 	client := NewClient(
 		WithBaseURL(server.URL),
-		WithRateLimit(rate.Limit(1)), // 1 request per second
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithEndpointTimeout("createPage", time.Minute),
+		WithEndpointTimeout("/getViews", 5*time.Second),
 	)
 
-	start := time.Now()
+	_, err := client.CreatePage(context.Background(), &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test",
+		Content:     NewContentBuilder().AddParagraph("Hello").Build(),
+	})
+	require.NoError(t, err)
 
-	// Make two requests
-	for i := 0; i < 2; i++ {
-		_, err := client.CreateAccount(context.Background(), &CreateAccountRequest{
-			ShortName: fmt.Sprintf("Test%d", i),
+	_, err = client.GetViews(context.Background(), &GetViewsRequest{Path: "Test"})
+	require.NoError(t, err)
+
+	_, err = client.GetPage(context.Background(), &GetPageRequest{Path: "Test"})
+	require.NoError(t, err)
+
+	assert.InDelta(t, float64(time.Minute), float64(deadlines["/createPage"]), float64(time.Second))
+	assert.InDelta(t, float64(5*time.Second), float64(deadlines["/getViews"]), float64(time.Second))
+	assert.Greater(t, deadlines["/createPage"], deadlines["/getViews"])
+
+	_, ok := deadlines["/getPage"]
+	assert.False(t, ok, "endpoints without a timeout should fall back to the client default")
+}
+
+func TestClientTimeoutPrecedence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	var deadline time.Duration
+	newClient := func(opts ...ClientOption) *Client {
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			deadline = 0
+			if d, ok := req.Context().Deadline(); ok {
+				deadline = time.Until(d)
+			}
+			return http.DefaultTransport.RoundTrip(req)
 		})
+		opts = append([]ClientOption{
+			WithBaseURL(server.URL),
+			WithHTTPClient(&http.Client{Transport: transport, Timeout: 30 * time.Second}),
+		}, opts...)
+		return NewClient(opts...)
+	}
+	getViews := func(t *testing.T, ctx context.Context, client *Client) time.Duration {
+		_, err := client.GetViews(ctx, &GetViewsRequest{Path: "Test"})
 		require.NoError(t, err)
+		return deadline
+	}
+	assertAbout := func(t *testing.T, want, got time.Duration) {
+		assert.InDelta(t, float64(want), float64(got), float64(time.Second))
 	}
 
-	duration := time.Since(start)
-	// Should take at least 1 second due to rate limiting
-	assert.True(t, duration >= 1*time.Second)
+	client := newClient(WithEndpointTimeout("getViews", time.Minute), WithDefaultTimeout(10*time.Second))
+
+	t.Run("context deadline", func(t *testing.T) {
+		// Longer than every other timeout, and still not cut short
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		assertAbout(t, time.Hour, getViews(t, ctx, client))
+	})
+
+	t.Run("endpoint timeout", func(t *testing.T) {
+		assertAbout(t, time.Minute, getViews(t, context.Background(), client))
+	})
+
+	t.Run("client default timeout", func(t *testing.T) {
+		client := newClient(WithEndpointTimeout("createPage", time.Minute), WithDefaultTimeout(10*time.Second))
+		assertAbout(t, 10*time.Second, getViews(t, context.Background(), client))
+	})
+
+	t.Run("HTTP client timeout", func(t *testing.T) {
+		assertAbout(t, 30*time.Second, getViews(t, context.Background(), newClient()))
+	})
+}
+
+func TestRedactToken(t *testing.T) {
+	token := "d3b25feccb89e508a9114afb82aa421fe2a9712b963b387cc5ad71e58722"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"json body", `{"access_token":"` + token + `","title":"Test"}`, `{"access_token":"d3b2...8722","title":"Test"}`},
+		{"json with spaces", `{"access_token": "` + token + `"}`, `{"access_token": "d3b2...8722"}`},
+		{"query string", "/getPageList?access_token=" + token + "&limit=10", "/getPageList?access_token=d3b2...8722&limit=10"},
+		{"short token", `{"access_token":"short"}`, `{"access_token":"*****"}`},
+		{"no token", "nothing to hide", "nothing to hide"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactToken(tt.input))
+		})
+	}
+}
+
+func TestClientErrorsRedactTokens(t *testing.T) {
+	token := "d3b25feccb89e508a9114afb82aa421fe2a9712b963b387cc5ad71e58722"
+
+	t.Run("echoed response body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("bad request: " + string(body)))
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+
+		_, err := client.GetAccountInfo(context.Background(), &GetAccountInfoRequest{AccessToken: token})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), token)
+		assert.Contains(t, err.Error(), "d3b2...8722")
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("connection reset while sending access_token=%s", token)
+		})
+		client := NewClient(
+			WithHTTPClient(&http.Client{Transport: transport}),
+			WithRetryConfig(RetryConfig{}),
+		)
+
+		_, err := client.GetAccountInfo(context.Background(), &GetAccountInfoRequest{AccessToken: token})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), token)
+	})
 }
 
 func TestClientContextCancellation(t *testing.T) {
@@ -412,6 +2013,27 @@ func TestClientContextCancellation(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "context deadline exceeded")
+	assert.NotErrorIs(t, err, ErrRateLimitWait)
+}
+
+func TestClientRateLimitWaitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1)))
+
+	// Saturate the limiter, so the next call has to wait about a second
+	_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-Article-12-15"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetViews(ctx, &GetViewsRequest{Path: "Test-Article-12-15"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRateLimitWait)
 }
 
 func TestConvertHTMLToPage(t *testing.T) {
@@ -483,6 +2105,20 @@ func TestConvertHTMLToPage(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "block elements inside inline ones are unwrapped",
+			html: `<html><body><p><a href="https://example.com"><span>link</span></a> <b><span>bold</span></b></p><dl><dt><div>Term</div></dt></dl></body></html>`,
+			expectedPage: &Page{
+				Content: []Node{
+					{Tag: "p", Children: []interface{}{
+						Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com"}, Children: []interface{}{"link"}},
+						" ",
+						Node{Tag: "strong", Children: []interface{}{"bold"}},
+					}},
+					{Tag: "p", Children: []interface{}{Node{Tag: "strong", Children: []interface{}{"Term"}}}},
+				},
+			},
+		},
 		{
 			name: "empty body",
 			html: `<html><head><title>Empty</title></head><body></body></html>`,
@@ -523,6 +2159,191 @@ func TestConvertHTMLToPage(t *testing.T) {
 	}
 }
 
+func TestConvertHTMLToPageCommentsAndDoctype(t *testing.T) {
+	client := NewClient()
+
+	t.Run("document", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(`<!DOCTYPE html><!-- header --><html><body><!-- start --><p>Text<!-- inline --></p><!-- end --></body></html>`, nil)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{{Tag: "p", Children: []interface{}{"Text"}}}, page.Content)
+	})
+
+	t.Run("only comments", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(`<!DOCTYPE html><html><body><!-- nothing to see --></body></html>`, nil)
+		require.NoError(t, err)
+		assert.Empty(t, page.Content)
+	})
+
+	t.Run("nodes passed directly", func(t *testing.T) {
+		// The parser places a doctype only at the document level, so build the tree by hand
+		body := &html.Node{Type: html.ElementNode, Data: "body"}
+		body.AppendChild(&html.Node{Type: html.DoctypeNode, Data: "html"})
+		body.AppendChild(&html.Node{Type: html.CommentNode, Data: "secret"})
+		body.AppendChild(&html.Node{Type: html.TextNode, Data: "visible"})
+
+		nodes, err := client.htmlNodeToTelegraphNodes(body, nil, 0, false)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{{Content: "visible"}}, nodes)
+	})
+}
+
+func TestConvertHTMLToPageMaxDepth(t *testing.T) {
+	client := NewClient()
+
+	t.Run("pathologically nested input", func(t *testing.T) {
+		html := "<html><body>" + strings.Repeat("<div>", 2000) + "deep" + "</body></html>"
+
+		_, err := client.ConvertHTMLToPage(html, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maximum nesting depth of 256")
+	})
+
+	t.Run("custom max depth", func(t *testing.T) {
+		html := "<html><body>" + strings.Repeat("<div>", 5) + "text" + strings.Repeat("</div>", 5) + "</body></html>"
+
+		_, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{MaxDepth: 4})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maximum nesting depth of 4")
+
+		page, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{MaxDepth: 5})
+		require.NoError(t, err)
+		assert.Len(t, page.Content, 1)
+	})
+}
+
+func TestConvertHTMLToPageCollapseBreaks(t *testing.T) {
+	client := NewClient()
+	html := `<html><body><p>First<br><br>
+<br>Second<br>Third</p></body></html>`
+
+	t.Run("disabled by default", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{
+			{Tag: "p", Children: []interface{}{
+				"First", Node{Tag: "br"}, Node{Tag: "br"}, "\n", Node{Tag: "br"}, "Second", Node{Tag: "br"}, "Third",
+			}},
+		}, page.Content)
+	})
+
+	t.Run("collapses consecutive breaks", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{CollapseBreaks: true})
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{
+			{Tag: "p", Children: []interface{}{
+				"First", Node{Tag: "br"}, Node{Tag: "br"}, "Second", Node{Tag: "br"}, "Third",
+			}},
+		}, page.Content)
+	})
+}
+
+func TestConvertHTMLToPageTagMapping(t *testing.T) {
+	html := `<html><body><p><mark>Important</mark> and <b>bold</b></p></body></html>`
+
+	t.Run("built-in mapping", func(t *testing.T) {
+		page, err := NewClient().ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{
+			{Tag: "p", Children: []interface{}{
+				Node{Tag: "p", Children: []interface{}{"Important"}}, " and ", Node{Tag: "strong", Children: []interface{}{"bold"}},
+			}},
+		}, page.Content)
+	})
+
+	t.Run("custom mapping overrides defaults", func(t *testing.T) {
+		client := NewClient(WithTagMapping(map[string]string{"mark": "strong", "b": "u"}))
+
+		page, err := client.ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{
+			{Tag: "p", Children: []interface{}{
+				Node{Tag: "strong", Children: []interface{}{"Important"}}, " and ", Node{Tag: "u", Children: []interface{}{"bold"}},
+			}},
+		}, page.Content)
+	})
+
+	t.Run("unsupported targets are ignored", func(t *testing.T) {
+		client := NewClient(WithTagMapping(map[string]string{"mark": "span", "b": "STRONG"}))
+
+		page, err := client.ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assertNodesEqual(t, []Node{
+			{Tag: "p", Children: []interface{}{
+				Node{Tag: "p", Children: []interface{}{"Important"}}, " and ", Node{Tag: "strong", Children: []interface{}{"bold"}},
+			}},
+		}, page.Content)
+	})
+}
+
+func TestConvertHTMLToPageAllowedAttrs(t *testing.T) {
+	client := NewClient()
+	html := `<html><body><iframe src="/embed/x" data-embed-id="42" data-theme="dark" width="640"></iframe><p data-embed-id="7">Text</p></body></html>`
+
+	t.Run("only href and src by default", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"src": "/embed/x"}, page.Content[0].Attrs)
+		assert.Empty(t, page.Content[1].Attrs)
+	})
+
+	t.Run("tag scoped attribute", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{AllowedAttrs: []string{"iframe:data-embed-id"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"src": "/embed/x", "data-embed-id": "42"}, page.Content[0].Attrs)
+		assert.Empty(t, page.Content[1].Attrs)
+	})
+
+	t.Run("prefix and global attributes", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{AllowedAttrs: []string{"iframe:data-*", "width"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"src": "/embed/x", "data-embed-id": "42", "data-theme": "dark", "width": "640"}, page.Content[0].Attrs)
+		assert.Empty(t, page.Content[1].Attrs)
+	})
+}
+
+func TestConvertHTMLToPageUppercaseAttrs(t *testing.T) {
+	client := NewClient()
+
+	t.Run("parsed markup", func(t *testing.T) {
+		page, err := client.ConvertHTMLToPage(`<html><body><P><A HREF="https://example.com">Link</A></P><IMG SRC="/file/a.jpg" Data-Id="1"></body></html>`,
+			&HTMLToPageOptions{AllowedAttrs: []string{"img:data-id"}})
+		require.NoError(t, err)
+		require.Len(t, page.Content, 2)
+		assert.Equal(t, map[string]string{"href": "https://example.com"}, page.Content[0].Children[0].(Node).Attrs)
+		assert.Equal(t, map[string]string{"src": "/file/a.jpg", "data-id": "1"}, page.Content[1].Attrs)
+	})
+
+	t.Run("node tree", func(t *testing.T) {
+		// Trees built by hand are not normalized by the parser
+		parent := &html.Node{Type: html.ElementNode, Data: "body"}
+		link := &html.Node{Type: html.ElementNode, Data: "a", Attr: []html.Attribute{{Key: "HREF", Val: "https://example.com"}}}
+		link.AppendChild(&html.Node{Type: html.TextNode, Data: "Link"})
+		parent.AppendChild(link)
+		parent.AppendChild(&html.Node{Type: html.ElementNode, Data: "img", Attr: []html.Attribute{{Key: "Src", Val: "/file/a.jpg"}}})
+
+		nodes, err := client.htmlNodeToTelegraphNodes(parent, nil, 0, false)
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+		assert.Equal(t, map[string]string{"href": "https://example.com"}, nodes[0].Attrs)
+		assert.Equal(t, map[string]string{"src": "/file/a.jpg"}, nodes[1].Attrs)
+	})
+}
+
+func TestConvertHTMLToPageDefinitionList(t *testing.T) {
+	client := NewClient()
+	html := `<html><body><dl><dt>Go</dt><dd>A <em>compiled</em> language</dd><dt>Telegraph</dt><dd>A publishing tool</dd></dl></body></html>`
+
+	page, err := client.ConvertHTMLToPage(html, nil)
+	require.NoError(t, err)
+	assertNodesEqual(t, []Node{
+		{Tag: "p", Children: []interface{}{Node{Tag: "strong", Children: []interface{}{"Go"}}}},
+		{Tag: "blockquote", Children: []interface{}{"A ", Node{Tag: "em", Children: []interface{}{"compiled"}}, " language"}},
+		{Tag: "p", Children: []interface{}{Node{Tag: "strong", Children: []interface{}{"Telegraph"}}}},
+		{Tag: "blockquote", Children: []interface{}{"A publishing tool"}},
+	}, page.Content)
+	assert.NoError(t, ValidateContent(page.Content))
+}
+
 // assertNodesEqual recursively compares two slices of Node objects
 func assertNodesEqual(t *testing.T, expected, actual []Node) bool {
 	if !assert.Len(t, actual, len(expected), "Node slices should have the same length") {
@@ -643,3 +2464,91 @@ func BenchmarkClientCreatePage(b *testing.B) {
 		}
 	}
 }
+
+func TestContentBuilderFromFetchedPage(t *testing.T) {
+	var edited []Node
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/getPage":
+			fmt.Fprint(w, `{"ok":true,"result":{"path":"Test-Article-12-15","title":"Test Article","content":[`+
+				`{"tag":"p","children":["Hello, ",{"tag":"a","attrs":{"href":"https://example.com"},"children":["world"]}]},`+
+				`{"tag":"img","attrs":{"src":"/file/abc.jpg"}}]}}`)
+		case "/editPage":
+			var req EditPageRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			edited = req.Content
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: req.Path}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	page, err := client.GetPage(ctx, &GetPageRequest{Path: "Test-Article-12-15", ReturnContent: true})
+	require.NoError(t, err)
+
+	content := NewContentBuilderFromNodes(page.Content).AddParagraph("Appended").Build()
+	require.Len(t, content, 3)
+	assert.Equal(t, Node{
+		Tag: "p",
+		Children: []interface{}{
+			"Hello, ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com"}, Children: []interface{}{"world"}},
+		},
+	}, content[0])
+	assert.Equal(t, Node{Tag: "img", Attrs: map[string]string{"src": "/file/abc.jpg"}}, content[1])
+
+	_, err = client.EditPage(ctx, &EditPageRequest{
+		AccessToken: "test-token",
+		Path:        page.Path,
+		Title:       page.Title,
+		Content:     content,
+	})
+	require.NoError(t, err)
+	require.Len(t, edited, 3)
+	assert.Equal(t, "p", edited[2].Tag)
+	assert.Equal(t, []interface{}{"Hello, ", map[string]interface{}{
+		"tag": "a", "attrs": map[string]interface{}{"href": "https://example.com"}, "children": []interface{}{"world"},
+	}}, edited[0].Children)
+}
+
+// benchmarkHTML returns a document with a mix of attributes, inline markup and containers
+func benchmarkHTML() string {
+	var doc strings.Builder
+	doc.WriteString(`<html><head><title>Benchmark</title></head><body>`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&doc, `<h2 id="s%d" class="title">Section %d</h2>`, i, i)
+		doc.WriteString(`<p class="lead" style="color: red">Some <b>bold</b> and <i>italic</i> text with <a href="https://example.com" target="_blank">a link</a>.</p>`)
+		doc.WriteString(`<div class="wrapper"><ul><li>One</li><li>Two</li></ul><img src="image.jpg" alt="An image"></div>`)
+	}
+	doc.WriteString(`</body></html>`)
+	return doc.String()
+}
+
+func BenchmarkConvertHTMLToPage(b *testing.B) {
+	doc := benchmarkHTML()
+	client := NewClient()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ConvertHTMLToPage(doc, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseHTMLBody(b *testing.B) {
+	doc, err := html.Parse(strings.NewReader(benchmarkHTML()))
+	require.NoError(b, err)
+	client := NewClient()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.parseHTMLBody(doc, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package telegraph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+)
+
+// uncompressedResendKey is the context key marking the uncompressed resend of
+// a request attempt whose compression was rejected
+type uncompressedResendKey struct{}
+
+// withUncompressedResend returns a context that marks a request as the
+// uncompressed resend of the attempt of ctx
+func withUncompressedResend(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncompressedResendKey{}, true)
+}
+
+// isUncompressedResend reports whether ctx was marked with withUncompressedResend
+func isUncompressedResend(ctx context.Context) bool {
+	resend, _ := ctx.Value(uncompressedResendKey{}).(bool)
+	return resend
+}
+
+// WithRequestCompression enables gzip compression of request bodies
+//
+// Compressed requests are sent with "Content-Encoding: gzip", which saves
+// upload bandwidth for large pages. If the server rejects a compressed request
+// with 415 Unsupported Media Type, the request is resent uncompressed and
+// compression is turned off for the rest of the client's lifetime.
+func WithRequestCompression(compress bool) ClientOption {
+	return func(c *Client) {
+		c.compressRequests = compress
+	}
+}
+
+// compressedBody returns the gzip-compressed request body and the header to
+// send it with, or jsonData and header unchanged if compression is off
+func (c *Client) compressedBody(jsonData []byte, header http.Header) ([]byte, http.Header, error) {
+	if !c.compressRequests || jsonData == nil || c.compressionRejected.Load() {
+		return jsonData, header, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(jsonData); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	compressedHeader := header.Clone()
+	if compressedHeader == nil {
+		compressedHeader = make(http.Header)
+	}
+	compressedHeader.Set("Content-Encoding", "gzip")
+	return buf.Bytes(), compressedHeader, nil
+}
+
+// rejectsCompression reports whether resp rejects the compression of a request
+// sent with header. If so, resp is closed and compression is turned off for
+// the client, so that the request can be resent uncompressed.
+func (c *Client) rejectsCompression(resp *http.Response, header http.Header) bool {
+	if resp.StatusCode != http.StatusUnsupportedMediaType || header.Get("Content-Encoding") == "" {
+		return false
+	}
+	resp.Body.Close()
+	c.compressionRejected.Store(true)
+	return true
+}
@@ -0,0 +1,99 @@
+package telegraph
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRequestCompression(t *testing.T) {
+	req := &CreatePageRequest{AccessToken: "test-token", Title: "Test", Content: NewContentBuilder().AddParagraph("Hello").Build()}
+	page := Page{Path: "Test-12-15", URL: "https://telegra.ph/Test-12-15"}
+
+	t.Run("compressed body sent", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			var body CreatePageRequest
+			require.NoError(t, json.NewDecoder(zr).Decode(&body))
+			assert.Equal(t, "Test", body.Title)
+
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: page})
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL), WithRequestCompression(true))
+		result, err := client.CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "Test-12-15", result.Path)
+		assert.Equal(t, []string{"gzip"}, encodings)
+	})
+
+	t.Run("fallback when rejected", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			if r.Header.Get("Content-Encoding") != "" {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.True(t, json.Valid(body))
+
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: page})
+		}))
+		defer server.Close()
+
+		var metrics []RequestMetrics
+		var hookAttempts []int
+		client := NewClient(WithBaseURL(server.URL), WithRequestCompression(true),
+			WithMetricsHook(func(ctx context.Context, m RequestMetrics) {
+				metrics = append(metrics, m)
+			}),
+			WithRequestHook(func(req *http.Request, attempt int) {
+				hookAttempts = append(hookAttempts, attempt)
+			}))
+
+		_, err := client.CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gzip", ""}, encodings)
+
+		// Compression stays off once rejected
+		_, err = client.CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gzip", "", ""}, encodings)
+
+		// The resend is not a retry
+		require.Len(t, metrics, 2)
+		assert.Zero(t, metrics[0].Retries)
+
+		// The request hook sees the resend as part of the first attempt
+		assert.Equal(t, []int{1, 1}, hookAttempts)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: page})
+		}))
+		defer server.Close()
+
+		_, err := NewClient(WithBaseURL(server.URL)).CreatePage(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{""}, encodings)
+	})
+}
@@ -170,3 +170,26 @@ func TestIntegrationRateLimiting(t *testing.T) {
 	// Should take at least 2 seconds for 3 requests with 1 RPS limit
 	assert.True(t, duration >= 2*time.Second, "Rate limiting should enforce delays")
 }
+
+func TestIntegrationDryValidateContent(t *testing.T) {
+	if os.Getenv("TELEGRAPH_INTEGRATION_TEST") != "1" {
+		t.Skip("Integration tests skipped. Set TELEGRAPH_INTEGRATION_TEST=1 to run.")
+	}
+
+	// Dry validation leaves a page behind, so use a throwaway account
+	client := telegraph.NewClient(telegraph.WithDryValidation())
+	ctx := context.Background()
+
+	account, err := client.CreateAccount(ctx, &telegraph.CreateAccountRequest{
+		ShortName: "DryValidation",
+	})
+	require.NoError(t, err)
+
+	content := telegraph.NewContentBuilder().
+		AddParagraph("Accepted content.").
+		Build()
+	assert.NoError(t, client.DryValidateContent(ctx, account.AccessToken, content))
+
+	// Content the API rejects
+	assert.Error(t, client.DryValidateContent(ctx, account.AccessToken, []telegraph.Node{{Tag: "script"}}))
+}
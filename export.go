@@ -0,0 +1,214 @@
+package telegraph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// ExportFormat is the output format of ExportAccount
+type ExportFormat int
+
+const (
+	// ExportJSON writes a single JSON array of pages
+	ExportJSON ExportFormat = iota
+	// ExportNDJSON writes one JSON page object per line (newline-delimited JSON)
+	ExportNDJSON
+)
+
+// ExportOption configures ExportAccount
+type ExportOption func(*exportOptions)
+
+// exportOptions holds the settings of ExportAccount
+type exportOptions struct {
+	manifestPath string
+	resume       bool
+}
+
+// WithExportManifest records the path of every exported page in a manifest file
+//
+// The manifest is an append-only log with one JSON string per line, the path of
+// a page, appended after the page is written. It is what Resume uses to skip
+// pages exported by an earlier, interrupted run. A line cut short by a crash is
+// ignored, so its page is exported again. Without Resume, an existing manifest
+// is truncated.
+func WithExportManifest(path string) ExportOption {
+	return func(o *exportOptions) {
+		o.manifestPath = path
+	}
+}
+
+// Resume skips pages already listed in the export manifest, continuing an
+// interrupted export. It requires WithExportManifest and ExportNDJSON, since a
+// JSON array cannot be continued; open the output in append mode when resuming.
+//
+// A page written just before an interruption may not be in the manifest yet,
+// and is then exported again, so consumers should ignore repeated paths.
+func Resume(resume bool) ExportOption {
+	return func(o *exportOptions) {
+		o.resume = resume
+	}
+}
+
+// ExportAccount writes every page of the account, including its content, to w
+//
+// Pages are fetched and written one at a time, so large accounts are exported
+// without holding all pages in memory. Each page is written as a Page object with
+// its path, URL, title, views and content. Long exports can be made resumable
+// with WithExportManifest and Resume.
+//
+// Example:
+//
+//	f, _ := os.OpenFile("pages.ndjson", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+//	defer f.Close()
+//	err := client.ExportAccount(ctx, token, f, telegraph.ExportNDJSON,
+//		telegraph.WithExportManifest("pages.manifest.jsonl"),
+//		telegraph.Resume(true),
+//	)
+func (c *Client) ExportAccount(ctx context.Context, accessToken string, w io.Writer, format ExportFormat, opts ...ExportOption) error {
+	if format != ExportJSON && format != ExportNDJSON {
+		return fmt.Errorf("unknown export format %d", format)
+	}
+
+	var options exportOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.resume && (options.manifestPath == "" || format != ExportNDJSON) {
+		return fmt.Errorf("resuming an export requires a manifest and the NDJSON format")
+	}
+
+	var manifest *os.File
+	skip := make(map[string]bool)
+	if options.manifestPath != "" {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if options.resume {
+			var err error
+			if skip, err = readExportManifest(options.manifestPath); err != nil {
+				return err
+			}
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+
+		var err error
+		if manifest, err = os.OpenFile(options.manifestPath, flags, 0644); err != nil {
+			return fmt.Errorf("failed to open export manifest: %w", err)
+		}
+		defer manifest.Close()
+		// Start on a new line if the last run was cut short mid-line
+		if options.resume {
+			if err := terminateLastLine(options.manifestPath, manifest); err != nil {
+				return fmt.Errorf("failed to write export manifest: %w", err)
+			}
+		}
+	}
+
+	// Encoder.Encode terminates each value with a newline, as NDJSON requires
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	separator := "["
+	err := c.ForEachPage(ctx, accessToken, func(listed Page) error {
+		if skip[listed.Path] {
+			return nil
+		}
+
+		page, err := c.getPage(ctx, &GetPageRequest{Path: listed.Path, ReturnContent: true})
+		if err != nil {
+			return fmt.Errorf("failed to export page %s: %w", listed.Path, err)
+		}
+
+		if format == ExportJSON {
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			separator = ","
+		}
+		if err := encoder.Encode(page); err != nil {
+			return err
+		}
+
+		if manifest == nil {
+			return nil
+		}
+		line, err := json.Marshal(listed.Path)
+		if err != nil {
+			return err
+		}
+		if _, err := manifest.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write export manifest: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if manifest != nil {
+		if err := manifest.Sync(); err != nil {
+			return fmt.Errorf("failed to write export manifest: %w", err)
+		}
+	}
+
+	if format == ExportJSON {
+		if separator == "[" {
+			_, err = io.WriteString(w, "[]\n")
+		} else {
+			_, err = io.WriteString(w, "]\n")
+		}
+	}
+	return err
+}
+
+// readExportManifest returns the paths listed in the export manifest at path,
+// ignoring lines that are not JSON strings. A missing manifest lists no paths.
+func readExportManifest(path string) (map[string]bool, error) {
+	exported := make(map[string]bool)
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return exported, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export manifest: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var exportedPath string
+		if json.Unmarshal(scanner.Bytes(), &exportedPath) == nil {
+			exported[exportedPath] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read export manifest: %w", err)
+	}
+	return exported, nil
+}
+
+// terminateLastLine writes a newline to w if the file at path is not empty and
+// does not end with one
+func terminateLastLine(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = w.Write([]byte("\n"))
+	}
+	return err
+}
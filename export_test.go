@@ -0,0 +1,161 @@
+package telegraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportServer serves getPageList and getPage for totalCount generated pages
+func newExportServer(t *testing.T, totalCount int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch r.URL.Path {
+		case "/getPageList":
+			var req GetPageListRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			pages := []Page{}
+			for i := req.Offset; i < req.Offset+req.Limit && i < totalCount; i++ {
+				pages = append(pages, Page{Path: fmt.Sprintf("Page-%d", i)})
+			}
+			result = PageList{TotalCount: totalCount, Pages: pages}
+		case "/getPage":
+			assert.Equal(t, "true", r.URL.Query().Get("return_content"))
+			path := r.URL.Query().Get("path")
+			result = Page{
+				Path:    path,
+				Title:   "Title of " + path,
+				Views:   len(path),
+				Content: []Node{{Tag: "p", Children: []interface{}{"Content of " + path}}},
+			}
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: result})
+	}))
+}
+
+func TestClientExportAccount(t *testing.T) {
+	server := newExportServer(t, 3)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, client.ExportAccount(context.Background(), "test-token", &buf, ExportNDJSON))
+
+		lines := 0
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var page map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &page), scanner.Text())
+
+			path := fmt.Sprintf("Page-%d", lines)
+			assert.Equal(t, path, page["path"])
+			assert.Equal(t, "Title of "+path, page["title"])
+			assert.EqualValues(t, len(path), page["views"])
+			assert.NotEmpty(t, page["content"])
+			lines++
+		}
+		assert.Equal(t, 3, lines)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, client.ExportAccount(context.Background(), "test-token", &buf, ExportJSON))
+
+		var pages []Page
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &pages))
+		require.Len(t, pages, 3)
+		assert.Equal(t, "Page-2", pages[2].Path)
+	})
+
+	t.Run("empty account", func(t *testing.T) {
+		empty := newExportServer(t, 0)
+		defer empty.Close()
+
+		var buf bytes.Buffer
+		require.NoError(t, NewClient(WithBaseURL(empty.URL)).ExportAccount(context.Background(), "test-token", &buf, ExportJSON))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
+
+// failingWriter fails every write after the first n
+type failingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	f.n--
+	return f.w.Write(p)
+}
+
+func TestClientExportAccountResume(t *testing.T) {
+	server := newExportServer(t, 5)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	manifest := filepath.Join(t.TempDir(), "export.manifest.jsonl")
+	opts := []ExportOption{WithExportManifest(manifest), Resume(true)}
+
+	// The first run is interrupted after two pages
+	var out bytes.Buffer
+	err := client.ExportAccount(context.Background(), "test-token", &failingWriter{w: &out, n: 2}, ExportNDJSON, opts...)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "\"Page-0\"\n\"Page-1\"\n", string(data))
+
+	// A line cut short by a crash is ignored
+	f, err := os.OpenFile(manifest, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`"Page-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The resumed run exports the remaining pages only
+	require.NoError(t, client.ExportAccount(context.Background(), "test-token", &out, ExportNDJSON, opts...))
+
+	var paths []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var page Page
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &page))
+		paths = append(paths, page.Path)
+	}
+	assert.Equal(t, []string{"Page-0", "Page-1", "Page-2", "Page-3", "Page-4"}, paths)
+
+	data, err = os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "\"Page-0\"\n\"Page-1\"\n\"Page-\n\"Page-2\"\n\"Page-3\"\n\"Page-4\"\n", string(data))
+
+	t.Run("manifest is truncated without resume", func(t *testing.T) {
+		require.NoError(t, client.ExportAccount(context.Background(), "test-token", io.Discard, ExportNDJSON, WithExportManifest(manifest)))
+		data, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, 5, bytes.Count(data, []byte("\n")))
+	})
+
+	t.Run("requires ndjson and manifest", func(t *testing.T) {
+		err := client.ExportAccount(context.Background(), "test-token", io.Discard, ExportJSON, opts...)
+		assert.Error(t, err)
+		err = client.ExportAccount(context.Background(), "test-token", io.Discard, ExportNDJSON, Resume(true))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package telegraph
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// FaultInjector is called before every request attempt with the API method name,
+// e.g. "createPage". A non-nil error makes the attempt fail without contacting the
+// API: a *FaultStatus is turned into a response with its status code, and any other
+// error is treated as a network error.
+type FaultInjector func(endpoint string) error
+
+// FaultStatus is an error that makes a FaultInjector simulate an HTTP response
+type FaultStatus struct {
+	StatusCode int
+	// Body is the response body (default: a JSON API error naming the status)
+	Body string
+}
+
+func (f *FaultStatus) Error() string {
+	return fmt.Sprintf("injected status code %d", f.StatusCode)
+}
+
+// InjectStatus returns an error that makes a FaultInjector simulate a response with the given status code
+func InjectStatus(statusCode int) error {
+	return &FaultStatus{StatusCode: statusCode}
+}
+
+// WithFaultInjector sets a function that can fail requests before they are sent,
+// to test how callers handle API failures. Injected faults go through the usual
+// retry logic, so e.g. an injected 429 is retried.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithFaultInjector(func(endpoint string) error {
+//		if endpoint == "createPage" {
+//			return telegraph.InjectStatus(http.StatusServiceUnavailable)
+//		}
+//		return nil
+//	}))
+func WithFaultInjector(injector FaultInjector) ClientOption {
+	return func(c *Client) {
+		c.faultInjector = injector
+	}
+}
+
+// response builds the simulated response for req
+func (f *FaultStatus) response(req *http.Request) *http.Response {
+	body := f.Body
+	if body == "" {
+		body = fmt.Sprintf(`{"ok":false,"error":"%s"}`, http.StatusText(f.StatusCode))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,98 @@
+package telegraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientFaultInjector(t *testing.T) {
+	retryConfig := RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	createReq := &CreatePageRequest{
+		AccessToken: "test-token",
+		Title:       "Test Article",
+		Content:     NewContentBuilder().AddParagraph("Hello").Build(),
+	}
+
+	t.Run("injected 429 is retried", func(t *testing.T) {
+		injected := 0
+		client := NewClient(
+			WithBaseURL("http://telegraph.invalid"),
+			WithRetryConfig(retryConfig),
+			WithFaultInjector(func(endpoint string) error {
+				assert.Equal(t, "createPage", endpoint)
+				injected++
+				return InjectStatus(http.StatusTooManyRequests)
+			}),
+		)
+
+		_, err := client.CreatePage(context.Background(), createReq)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "request failed after 4 attempts: received status code 429")
+		assert.Equal(t, 4, injected)
+	})
+
+	t.Run("recovers once faults stop", func(t *testing.T) {
+		injected, sent := 0, 0
+		client := NewClient(
+			WithBaseURL("http://telegraph.invalid"),
+			WithRetryConfig(retryConfig),
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				sent++
+				return (&FaultStatus{StatusCode: http.StatusOK, Body: `{"ok":true,"result":{"path":"Test-Article-12-15"}}`}).response(req), nil
+			})}),
+			WithFaultInjector(func(endpoint string) error {
+				injected++
+				if injected <= 2 {
+					return InjectStatus(http.StatusTooManyRequests)
+				}
+				return nil
+			}),
+		)
+
+		page, err := client.CreatePage(context.Background(), createReq)
+		require.NoError(t, err)
+		assert.Equal(t, "Test-Article-12-15", page.Path)
+		assert.Equal(t, 3, injected)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("injected status without retry", func(t *testing.T) {
+		client := NewClient(WithFaultInjector(func(string) error {
+			return &FaultStatus{StatusCode: http.StatusBadRequest, Body: `{"error_code":400,"description":"PAGE_NOT_FOUND"}`}
+		}))
+
+		_, err := client.GetPage(context.Background(), &GetPageRequest{Path: "Missing"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, ErrorKindPageNotFound, apiErr.Kind)
+	})
+
+	t.Run("injected error", func(t *testing.T) {
+		errDown := errors.New("telegraph is down")
+		injected := 0
+		client := NewClient(
+			WithRetryConfig(retryConfig),
+			WithFaultInjector(func(string) error {
+				injected++
+				return errDown
+			}),
+		)
+
+		// Writes are not retried on network errors
+		_, err := client.CreatePage(context.Background(), createReq)
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, 1, injected)
+
+		// Reads are
+		injected = 0
+		_, err = client.GetPage(context.Background(), &GetPageRequest{Path: "Test-Article-12-15"})
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, 4, injected)
+	})
+}
@@ -0,0 +1,123 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// fuzzSeeds are HTML inputs used to seed the converter fuzz targets
+var fuzzSeeds = []string{
+	`<html><head><title>T</title></head><body><p>Hello, <b>world</b>!</p></body></html>`,
+	`<!DOCTYPE html><html><body><!-- comment --><p>Text</p></body></html>`,
+	`<body><div><span><a href="https://example.com">link</a></span></div></body>`,
+	`<body><p><b><i><u>unbalanced</p></b>text</i></body>`,
+	`<body><dl><dt>Term</dt><dd>Definition</dd></dl></body>`,
+	`<body><ul><li>One<li>Two</ul><table><tr><td>cell</td></tr></table></body>`,
+	`<body><br><br> <br><img src="x.jpg"><script>alert(1)</script><style>p{}</style></body>`,
+	`<body><svg><foreignObject><p>inside</p></foreignObject></svg><math><mi>x</mi></math></body>`,
+	`<body><template><p>t</p></template><noscript><p>n</p></noscript></body>`,
+	`<frameset><frame src="a"></frameset>`,
+	`<body>` + strings.Repeat("<div>", 300) + `deep` + `</body>`,
+}
+
+// checkFuzzContent fails the test if content contains nodes Telegraph would reject
+func checkFuzzContent(t *testing.T, nodes []Node) {
+	t.Helper()
+
+	if _, err := json.Marshal(nodes); err != nil {
+		t.Fatalf("content cannot be serialized: %v", err)
+	}
+
+	// inline is the closest inline ancestor of a value, if any
+	type entry struct {
+		value  interface{}
+		inline string
+	}
+
+	stack := make([]entry, 0, len(nodes))
+	for _, node := range nodes {
+		stack = append(stack, entry{node, ""})
+	}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch n := top.value.(type) {
+		case string:
+			if n == "" {
+				t.Fatalf("empty text child")
+			}
+		case Node:
+			if n.Tag == "" {
+				if n.Content == "" || n.Children != nil || n.Attrs != nil {
+					t.Fatalf("malformed text node: %#v", n)
+				}
+				continue
+			}
+			if !supportedTags[n.Tag] {
+				t.Fatalf("unsupported tag <%s>", n.Tag)
+			}
+			if n.Content != "" {
+				t.Fatalf("element <%s> has text content", n.Tag)
+			}
+			if blockTags[n.Tag] && top.inline != "" {
+				t.Fatalf("block tag <%s> inside inline tag <%s>", n.Tag, top.inline)
+			}
+			inline := top.inline
+			if inlineTags[n.Tag] && inline == "" {
+				inline = n.Tag
+			}
+			for _, child := range n.Children {
+				if child == nil {
+					t.Fatalf("nil child in <%s>", n.Tag)
+				}
+				stack = append(stack, entry{child, inline})
+			}
+		default:
+			t.Fatalf("unexpected child type %T", top.value)
+		}
+	}
+}
+
+func FuzzConvertHTMLToPage(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	client := NewClient()
+	f.Fuzz(func(t *testing.T, input string) {
+		page, err := client.ConvertHTMLToPage(input, &HTMLToPageOptions{CollapseBreaks: true})
+		if err != nil {
+			return
+		}
+		checkFuzzContent(t, page.Content)
+	})
+}
+
+func FuzzHTMLNodeToTelegraphNodes(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	client := NewClient()
+	f.Fuzz(func(t *testing.T, input string) {
+		body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		fragment, err := html.ParseFragment(strings.NewReader(input), body)
+		if err != nil {
+			return
+		}
+		for _, n := range fragment {
+			body.AppendChild(n)
+		}
+
+		nodes, err := client.htmlNodeToTelegraphNodes(body, nil, 0, false)
+		if err != nil {
+			return
+		}
+		checkFuzzContent(t, nodes)
+	})
+}
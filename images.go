@@ -0,0 +1,58 @@
+package telegraph
+
+import "strings"
+
+// ImageExtractionOptions represents optional behavior of ExtractImagesWithOptions
+type ImageExtractionOptions struct {
+	// IncludeVideos adds the src of video nodes
+	IncludeVideos bool
+	// IncludeEmbeds adds the src of iframe nodes, such as YouTube embeds
+	IncludeEmbeds bool
+	// Dedupe keeps only the first occurrence of each URL
+	Dedupe bool
+}
+
+// ExtractImages returns the src of every img node of content, in document order
+//
+// Images are found at any depth, such as inside figures or paragraphs. The
+// URLs are returned as written, so Telegraph files have relative paths such as
+// "/file/abc.jpg". Use ExtractImagesWithOptions to include videos and embeds
+// or to drop duplicates.
+//
+// Example:
+//
+//	gallery := telegraph.ExtractImages(page.Content)
+func ExtractImages(nodes []Node) []string {
+	return ExtractImagesWithOptions(nodes, ImageExtractionOptions{})
+}
+
+// ExtractImagesWithOptions is like ExtractImages but also applies the options enabled in opts
+func ExtractImagesWithOptions(nodes []Node, opts ImageExtractionOptions) []string {
+	tags := map[string]bool{"img": true, "video": opts.IncludeVideos, "iframe": opts.IncludeEmbeds}
+	seen := make(map[string]bool)
+	var srcs []string
+
+	var walk func(children []interface{})
+	walk = func(children []interface{}) {
+		for _, child := range children {
+			node, ok := asNode(child)
+			if !ok {
+				continue
+			}
+			if src := node.Attrs["src"]; src != "" && tags[strings.ToLower(node.Tag)] {
+				if !opts.Dedupe || !seen[src] {
+					seen[src] = true
+					srcs = append(srcs, src)
+				}
+			}
+			walk(node.Children)
+		}
+	}
+
+	children := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		children[i] = node
+	}
+	walk(children)
+	return srcs
+}
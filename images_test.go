@@ -0,0 +1,54 @@
+package telegraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractImages(t *testing.T) {
+	content := []Node{
+		{Tag: "img", Attrs: map[string]string{"src": "/file/top.jpg"}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "/file/figure.jpg"}},
+			Node{Tag: "figcaption", Children: []interface{}{"Caption"}},
+		}},
+		{Tag: "p", Children: []interface{}{
+			"Inline ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "/file/linked.jpg"}, Children: []interface{}{
+				&Node{Tag: "IMG", Attrs: map[string]string{"src": "https://example.com/nested.png"}},
+			}},
+		}},
+		{Tag: "figure", Children: []interface{}{
+			map[string]interface{}{"tag": "video", "attrs": map[string]interface{}{"src": "/file/clip.mp4"}},
+		}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "iframe", Attrs: map[string]string{"src": "https://www.youtube.com/embed/abc"}},
+		}},
+		{Tag: "img", Attrs: map[string]string{"src": "/file/top.jpg"}},
+		{Tag: "img"},
+	}
+
+	t.Run("images only", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"/file/top.jpg",
+			"/file/figure.jpg",
+			"https://example.com/nested.png",
+			"/file/top.jpg",
+		}, ExtractImages(content))
+	})
+
+	t.Run("with videos and embeds, deduped", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"/file/top.jpg",
+			"/file/figure.jpg",
+			"https://example.com/nested.png",
+			"/file/clip.mp4",
+			"https://www.youtube.com/embed/abc",
+		}, ExtractImagesWithOptions(content, ImageExtractionOptions{IncludeVideos: true, IncludeEmbeds: true, Dedupe: true}))
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		assert.Nil(t, ExtractImages([]Node{{Tag: "p", Children: []interface{}{"Text"}}}))
+	})
+}
@@ -0,0 +1,124 @@
+package telegraph
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LinkStatus is the result of checking one URL of content
+type LinkStatus struct {
+	// URL is the absolute URL that was checked
+	URL string
+	// StatusCode is the HTTP status code of the response, or 0 if none was received
+	StatusCode int
+	// Err is the error making the request, if any
+	Err error
+}
+
+// Broken reports whether the URL failed to respond or responded with a 4xx or 5xx status
+func (s LinkStatus) Broken() bool {
+	return s.Err != nil || s.StatusCode >= 400
+}
+
+// CheckLinks requests every distinct link and image URL of content and reports its status
+//
+// The href of a nodes and the src of img nodes are checked, in document order,
+// using at most concurrency requests at a time and the client's HTTP client.
+// Relative URLs are resolved against telegra.ph, and anchors and URLs other
+// than http and https, such as mailto: links, are skipped. Each URL is requested
+// with HEAD, or with GET if the server does not allow HEAD. The Telegraph API
+// rate limit does not apply. If ctx is done before every URL was checked, only
+// ctx's error is returned.
+//
+// Example:
+//
+//	statuses, err := client.CheckLinks(ctx, content, 4)
+//	for _, status := range statuses {
+//		if status.Broken() {
+//			log.Printf("broken link %s: %d %v", status.URL, status.StatusCode, status.Err)
+//		}
+//	}
+func (c *Client) CheckLinks(ctx context.Context, nodes []Node, concurrency int) ([]LinkStatus, error) {
+	urls := contentLinks(nodes)
+	statuses := make([]LinkStatus, len(urls))
+	scheduled := runBatch(ctx, len(urls), concurrency, func(i int) {
+		statuses[i] = c.checkLink(ctx, urls[i])
+	})
+	if scheduled < len(urls) {
+		return nil, ctx.Err()
+	}
+	return statuses, nil
+}
+
+// checkLink requests rawURL with HEAD, falling back to GET if HEAD is not allowed
+func (c *Client) checkLink(ctx context.Context, rawURL string) LinkStatus {
+	status := LinkStatus{URL: rawURL}
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			status.Err = err
+			return status
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			status.Err = err
+			return status
+		}
+		resp.Body.Close()
+
+		status.StatusCode = resp.StatusCode
+		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status
+}
+
+// contentLinks returns the distinct absolute http and https URLs linked from
+// a nodes and embedded by img nodes, in document order
+func contentLinks(nodes []Node) []string {
+	base, _ := url.Parse(telegraphSite + "/")
+	seen := make(map[string]bool)
+	var links []string
+	add := func(ref string) {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || strings.HasPrefix(ref, "#") {
+			return
+		}
+		u, err := base.Parse(ref)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.Fragment = ""
+		if link := u.String(); !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	var walk func(children []interface{})
+	walk = func(children []interface{}) {
+		for _, child := range children {
+			node, ok := asNode(child)
+			if !ok {
+				continue
+			}
+			switch strings.ToLower(node.Tag) {
+			case "a":
+				add(node.Attrs["href"])
+			case "img":
+				add(node.Attrs["src"])
+			}
+			walk(node.Children)
+		}
+	}
+
+	children := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		children[i] = node
+	}
+	walk(children)
+	return links
+}
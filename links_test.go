@@ -0,0 +1,135 @@
+package telegraph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCheckLinks(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], r.Method)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/ok", "/image.png":
+			w.WriteHeader(http.StatusOK)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	link := func(href string) Node {
+		return Node{Tag: "a", Attrs: map[string]string{"href": href}, Children: []interface{}{"link"}}
+	}
+	content := []Node{
+		{Tag: "p", Children: []interface{}{link(server.URL + "/ok"), link(server.URL + "/missing"), link("#section"), link("mailto:me@example.com")}},
+		{Tag: "figure", Children: []interface{}{
+			map[string]interface{}{"tag": "img", "attrs": map[string]interface{}{"src": server.URL + "/image.png"}},
+		}},
+		{Tag: "p", Children: []interface{}{link(server.URL + "/error"), link(server.URL + "/no-head"), link(server.URL + "/ok#again")}},
+	}
+
+	client := NewClient()
+
+	t.Run("mixed statuses", func(t *testing.T) {
+		statuses, err := client.CheckLinks(context.Background(), content, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []LinkStatus{
+			{URL: server.URL + "/ok", StatusCode: 200},
+			{URL: server.URL + "/missing", StatusCode: 404},
+			{URL: server.URL + "/image.png", StatusCode: 200},
+			{URL: server.URL + "/error", StatusCode: 500},
+			{URL: server.URL + "/no-head", StatusCode: 200},
+		}, statuses)
+
+		var broken []string
+		for _, status := range statuses {
+			if status.Broken() {
+				broken = append(broken, status.URL)
+			}
+		}
+		assert.Equal(t, []string{server.URL + "/missing", server.URL + "/error"}, broken)
+
+		// Each URL is requested once, with GET only after HEAD is refused
+		assert.Equal(t, []string{"HEAD"}, requests["/ok"])
+		assert.Equal(t, []string{"HEAD", "GET"}, requests["/no-head"])
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		statuses, err := client.CheckLinks(context.Background(), []Node{link(closed.URL)}, 1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Error(t, statuses[0].Err)
+		assert.Zero(t, statuses[0].StatusCode)
+		assert.True(t, statuses[0].Broken())
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		statuses, err := client.CheckLinks(ctx, content, 2)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, statuses)
+	})
+
+	t.Run("canceled after every URL was checked", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var checked int
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			checked++
+			if checked == 2 {
+				cancel()
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		})
+		client := NewClient(WithHTTPClient(&http.Client{Transport: transport}))
+
+		statuses, err := client.CheckLinks(ctx, []Node{link("https://example.com/a"), link("https://example.com/b")}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []LinkStatus{
+			{URL: "https://example.com/a", StatusCode: 200},
+			{URL: "https://example.com/b", StatusCode: 200},
+		}, statuses)
+	})
+}
+
+func TestContentLinks(t *testing.T) {
+	content := []Node{
+		{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+		{Tag: "p", Children: []interface{}{
+			Node{Tag: "A", Attrs: map[string]string{"href": "https://example.com/page"}},
+			Node{Tag: "a", Attrs: map[string]string{"href": "tel:+123"}},
+			Node{Tag: "a", Attrs: map[string]string{"href": "Other-Page-12-15"}},
+		}},
+	}
+
+	assert.Equal(t, []string{
+		"https://telegra.ph/file/image.jpg",
+		"https://example.com/page",
+		"https://telegra.ph/Other-Page-12-15",
+	}, contentLinks(content))
+}
@@ -0,0 +1,134 @@
+package telegraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LintSeverity is the severity of a LintIssue
+type LintSeverity int
+
+const (
+	// LintWarning is used for content that is accepted but may be hard to use
+	LintWarning LintSeverity = iota
+	// LintError is used for content that is inaccessible to some readers
+	LintError
+)
+
+// String returns a lowercase name for the severity
+func (s LintSeverity) String() string {
+	if s == LintError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Lint rule names reported in LintIssue.Rule
+const (
+	LintRuleImageCaption = "image-caption"
+	LintRuleLinkText     = "link-text"
+	LintRuleEmptyHeading = "empty-heading"
+)
+
+// LintIssue describes an accessibility issue found by LintContent
+type LintIssue struct {
+	// Path is the path of the offending node, e.g. "content[1].children[0]"
+	Path     string
+	Severity LintSeverity
+	// Rule is the name of the rule that reported the issue
+	Rule    string
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", i.Path, i.Severity, i.Message, i.Rule)
+}
+
+// nonDescriptiveLinkText lists link texts that say nothing about the link target
+var nonDescriptiveLinkText = map[string]bool{
+	"click here": true, "click": true, "here": true, "link": true, "this": true,
+	"more": true, "read more": true, "learn more": true, "this link": true,
+}
+
+// LintContent checks content for common accessibility issues
+//
+// The following issues are reported:
+//   - images without a figcaption; Telegraph drops alt text, so a caption is
+//     the only text description readers get
+//   - links whose text does not describe the target, such as "click here"
+//   - headings without text
+//
+// Example:
+//
+//	for _, issue := range telegraph.LintContent(content) {
+//		fmt.Println(issue)
+//	}
+func LintContent(nodes []Node) []LintIssue {
+	type entry struct {
+		node Node
+		path string
+		// captioned is set for children of a figure that has a figcaption
+		captioned bool
+	}
+
+	stack := make([]entry, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		stack = append(stack, entry{node: nodes[i], path: fmt.Sprintf("content[%d]", i)})
+	}
+
+	var issues []LintIssue
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := top.node
+
+		switch strings.ToLower(node.Tag) {
+		case "img":
+			if !top.captioned {
+				issues = append(issues, LintIssue{
+					Path:     top.path,
+					Severity: LintWarning,
+					Rule:     LintRuleImageCaption,
+					Message:  "image has no figcaption describing it",
+				})
+			}
+		case "a":
+			text := strings.ToLower(strings.Trim(strings.TrimSpace(PlainText([]Node{node})), ".:!"))
+			if text == "" || nonDescriptiveLinkText[text] {
+				issues = append(issues, LintIssue{
+					Path:     top.path,
+					Severity: LintWarning,
+					Rule:     LintRuleLinkText,
+					Message:  fmt.Sprintf("link text %q does not describe the link target", text),
+				})
+			}
+		case "h3", "h4":
+			if strings.TrimSpace(PlainText([]Node{node})) == "" {
+				issues = append(issues, LintIssue{
+					Path:     top.path,
+					Severity: LintError,
+					Rule:     LintRuleEmptyHeading,
+					Message:  "heading has no text",
+				})
+			}
+		}
+
+		captioned := false
+		if strings.EqualFold(node.Tag, "figure") {
+			for _, child := range node.Children {
+				if ch, ok := asNode(child); ok && strings.EqualFold(ch.Tag, "figcaption") && strings.TrimSpace(PlainText([]Node{ch})) != "" {
+					captioned = true
+				}
+			}
+		}
+
+		for i := len(node.Children) - 1; i >= 0; i-- {
+			child, ok := asNode(node.Children[i])
+			if !ok {
+				continue
+			}
+			stack = append(stack, entry{node: child, path: fmt.Sprintf("%s.children[%d]", top.path, i), captioned: captioned})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,48 @@
+package telegraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintContent(t *testing.T) {
+	content := []Node{
+		{Tag: "img", Attrs: map[string]string{"src": "/file/a.jpg"}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "/file/b.jpg"}},
+			Node{Tag: "figcaption", Children: []interface{}{"A described image"}},
+		}},
+		{Tag: "p", Children: []interface{}{
+			"To download, ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com"}, Children: []interface{}{"Click here"}},
+			" or read the ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com/docs"}, Children: []interface{}{"installation guide"}},
+		}},
+		{Tag: "h3", Children: []interface{}{" "}},
+		{Tag: "h4", Children: []interface{}{"Section"}},
+	}
+
+	issues := LintContent(content)
+	assert.Equal(t, []LintIssue{
+		{Path: "content[0]", Severity: LintWarning, Rule: LintRuleImageCaption, Message: "image has no figcaption describing it"},
+		{Path: "content[2].children[1]", Severity: LintWarning, Rule: LintRuleLinkText, Message: `link text "click here" does not describe the link target`},
+		{Path: "content[3]", Severity: LintError, Rule: LintRuleEmptyHeading, Message: "heading has no text"},
+	}, issues)
+
+	assert.Equal(t, `content[3]: error: heading has no text (empty-heading)`, issues[2].String())
+
+	t.Run("empty caption", func(t *testing.T) {
+		issues := LintContent([]Node{{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "/file/a.jpg"}},
+			Node{Tag: "figcaption"},
+		}}})
+		if assert.Len(t, issues, 1) {
+			assert.Equal(t, "content[0].children[0]", issues[0].Path)
+		}
+	})
+
+	t.Run("clean content", func(t *testing.T) {
+		assert.Empty(t, LintContent(NewContentBuilder().AddHeading("Title", 3).AddParagraph("Text").Build()))
+	})
+}
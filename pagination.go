@@ -0,0 +1,228 @@
+package telegraph
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MaxPageListLimit is the maximum number of pages returned by a single getPageList call
+const MaxPageListLimit = 200
+
+// ErrOffsetOutOfRange is returned when a page list offset points past the account's last page
+var ErrOffsetOutOfRange = errors.New("offset is past the end of the page list")
+
+// ValidateOffset checks Offset against the account's known total page count
+func (r *GetPageListRequest) ValidateOffset(totalCount int) error {
+	if r.Offset > 0 && r.Offset >= totalCount {
+		return fmt.Errorf("%w: offset %d, total_count %d", ErrOffsetOutOfRange, r.Offset, totalCount)
+	}
+	return nil
+}
+
+// ClampOffset limits Offset to the range [0, totalCount]
+func (r *GetPageListRequest) ClampOffset(totalCount int) {
+	if r.Offset > totalCount {
+		r.Offset = totalCount
+	}
+	if r.Offset < 0 {
+		r.Offset = 0
+	}
+}
+
+// PageIterator walks through all pages of an account, fetching them in batches
+//
+// Example:
+//
+//	it := client.NewPageIterator(&telegraph.GetPageListRequest{AccessToken: token})
+//	for it.Next(ctx) {
+//		fmt.Println(it.Page().Title)
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type PageIterator struct {
+	client     *Client
+	req        GetPageListRequest
+	totalCount int
+	fetched    bool
+	buffer     []Page
+	current    Page
+	err        error
+	done       bool
+}
+
+// NewPageIterator creates an iterator starting at req.Offset and fetching req.Limit
+// pages per call (MaxPageListLimit when Limit is 0)
+func (c *Client) NewPageIterator(req *GetPageListRequest) *PageIterator {
+	if req == nil {
+		return &PageIterator{client: c, err: ErrNilRequest}
+	}
+	it := &PageIterator{
+		client:     c,
+		req:        *req,
+		totalCount: -1,
+	}
+	if it.req.Limit == 0 {
+		it.req.Limit = MaxPageListLimit
+	}
+	return it
+}
+
+// Next advances the iterator to the next page, fetching more pages when needed.
+// It returns false when all pages have been visited or an error occurred.
+func (it *PageIterator) Next(ctx context.Context) bool {
+	if it.err != nil || it.done {
+		return false
+	}
+
+	if len(it.buffer) == 0 {
+		if it.fetched && it.req.Offset >= it.totalCount {
+			it.done = true
+			return false
+		}
+
+		pageList, err := it.client.GetPageList(ctx, &it.req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		// Paging past the end on the very first request means the caller's offset is wrong
+		if !it.fetched {
+			if err := it.req.ValidateOffset(pageList.TotalCount); err != nil {
+				it.err = err
+				return false
+			}
+		}
+
+		it.fetched = true
+		it.totalCount = pageList.TotalCount
+		it.buffer = pageList.Pages
+		it.req.Offset += len(pageList.Pages)
+
+		// Guard against the page list shrinking while iterating
+		if len(it.buffer) == 0 {
+			it.done = true
+			return false
+		}
+	}
+
+	it.current = it.buffer[0]
+	it.buffer = it.buffer[1:]
+	return true
+}
+
+// Page returns the current page
+func (it *PageIterator) Page() Page {
+	return it.current
+}
+
+// Err returns the first error encountered during iteration
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// TotalCount returns the account's total page count, or -1 before the first fetch
+func (it *PageIterator) TotalCount() int {
+	return it.totalCount
+}
+
+// GetAllPages returns every page of the account, starting at req.Offset
+//
+// Example:
+//
+//	pages, err := client.GetAllPages(ctx, &telegraph.GetPageListRequest{AccessToken: token})
+func (c *Client) GetAllPages(ctx context.Context, req *GetPageListRequest) ([]Page, error) {
+	var pages []Page
+	it := c.NewPageIterator(req)
+	for it.Next(ctx) {
+		pages = append(pages, it.Page())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// ErrorPolicy determines how ForEachPageWithPolicy handles callback errors
+type ErrorPolicy int
+
+const (
+	// StopOnError stops at the first callback error and returns it
+	StopOnError ErrorPolicy = iota
+	// ContinueOnError visits every page and returns all callback errors joined together
+	ContinueOnError
+)
+
+// ForEachPage calls fn for every page of the account, stopping at the first error
+//
+// Errors returned by fn are returned as-is, and errors fetching the page list
+// are returned once iteration stops.
+//
+// Example:
+//
+//	err := client.ForEachPage(ctx, token, func(page telegraph.Page) error {
+//		fmt.Println(page.Title)
+//		return nil
+//	})
+func (c *Client) ForEachPage(ctx context.Context, accessToken string, fn func(Page) error) error {
+	return c.ForEachPageWithPolicy(ctx, accessToken, StopOnError, fn)
+}
+
+// ForEachPageWithPolicy is like ForEachPage, but with ContinueOnError every page
+// is visited and the callback errors are returned joined together with errors.Join.
+// Errors fetching the page list always stop iteration.
+//
+// Example:
+//
+//	err := client.ForEachPageWithPolicy(ctx, token, telegraph.ContinueOnError, func(page telegraph.Page) error {
+//		return export(page)
+//	})
+func (c *Client) ForEachPageWithPolicy(ctx context.Context, accessToken string, policy ErrorPolicy, fn func(Page) error) error {
+	var errs []error
+	it := c.NewPageIterator(&GetPageListRequest{AccessToken: accessToken})
+	for it.Next(ctx) {
+		if err := fn(it.Page()); err != nil {
+			if policy != ContinueOnError {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// TopPages returns the n most viewed pages of the account, by views descending
+//
+// Every page of the account is fetched, MaxPageListLimit at a time and subject
+// to the client's rate limit. Pages with the same number of views keep the
+// order of the page list, newest first. Fewer than n pages are returned if the
+// account has fewer pages.
+//
+// Example:
+//
+//	top, err := client.TopPages(ctx, token, 10)
+func (c *Client) TopPages(ctx context.Context, accessToken string, n int) ([]Page, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive, got %d", n)
+	}
+
+	pages, err := c.GetAllPages(ctx, &GetPageListRequest{AccessToken: accessToken, Limit: MaxPageListLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(pages, func(a, b Page) int {
+		return cmp.Compare(b.Views, a.Views)
+	})
+	if len(pages) > n {
+		pages = pages[:n]
+	}
+	return pages, nil
+}
@@ -0,0 +1,202 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPageListServer serves a getPageList endpoint backed by totalCount generated pages
+func newPageListServer(t *testing.T, totalCount int, calls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getPageList", r.URL.Path)
+		if calls != nil {
+			*calls++
+		}
+
+		var req GetPageListRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		pages := []Page{}
+		for i := req.Offset; i < req.Offset+req.Limit && i < totalCount; i++ {
+			pages = append(pages, Page{Path: fmt.Sprintf("Page-%d", i), Title: fmt.Sprintf("Page %d", i)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{
+			Ok:     true,
+			Result: PageList{TotalCount: totalCount, Pages: pages},
+		})
+	}))
+}
+
+func TestGetPageListRequestOffset(t *testing.T) {
+	t.Run("validate offset", func(t *testing.T) {
+		assert.NoError(t, (&GetPageListRequest{Offset: 0}).ValidateOffset(0))
+		assert.NoError(t, (&GetPageListRequest{Offset: 9}).ValidateOffset(10))
+
+		err := (&GetPageListRequest{Offset: 10}).ValidateOffset(10)
+		assert.ErrorIs(t, err, ErrOffsetOutOfRange)
+		assert.Contains(t, err.Error(), "offset 10, total_count 10")
+	})
+
+	t.Run("clamp offset", func(t *testing.T) {
+		req := &GetPageListRequest{Offset: 25}
+		req.ClampOffset(10)
+		assert.Equal(t, 10, req.Offset)
+
+		req = &GetPageListRequest{Offset: -1}
+		req.ClampOffset(10)
+		assert.Equal(t, 0, req.Offset)
+	})
+}
+
+func TestPageIterator(t *testing.T) {
+	t.Run("iterates over all pages", func(t *testing.T) {
+		calls := 0
+		server := newPageListServer(t, 5, &calls)
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+
+		pages, err := client.GetAllPages(context.Background(), &GetPageListRequest{
+			AccessToken: "test-token",
+			Limit:       2,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, pages, 5)
+		assert.Equal(t, "Page-0", pages[0].Path)
+		assert.Equal(t, "Page-4", pages[4].Path)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("offset past the end", func(t *testing.T) {
+		server := newPageListServer(t, 5, nil)
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+
+		it := client.NewPageIterator(&GetPageListRequest{
+			AccessToken: "test-token",
+			Offset:      7,
+		})
+
+		assert.False(t, it.Next(context.Background()))
+		assert.ErrorIs(t, it.Err(), ErrOffsetOutOfRange)
+		assert.Equal(t, -1, it.TotalCount())
+	})
+
+	t.Run("empty account", func(t *testing.T) {
+		server := newPageListServer(t, 0, nil)
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+
+		pages, err := client.GetAllPages(context.Background(), &GetPageListRequest{AccessToken: "test-token"})
+		require.NoError(t, err)
+		assert.Empty(t, pages)
+	})
+}
+
+func TestClientForEachPage(t *testing.T) {
+	server := newPageListServer(t, 5, nil)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	errThird := errors.New("third page failed")
+	visit := func(visited *[]string) func(Page) error {
+		return func(page Page) error {
+			*visited = append(*visited, page.Path)
+			if page.Path == "Page-2" {
+				return errThird
+			}
+			return nil
+		}
+	}
+
+	t.Run("stop on error", func(t *testing.T) {
+		var visited []string
+		err := client.ForEachPage(context.Background(), "test-token", visit(&visited))
+		assert.Equal(t, errThird, err)
+		assert.Equal(t, []string{"Page-0", "Page-1", "Page-2"}, visited)
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		var visited []string
+		err := client.ForEachPageWithPolicy(context.Background(), "test-token", ContinueOnError, visit(&visited))
+		assert.ErrorIs(t, err, errThird)
+		assert.Equal(t, []string{"Page-0", "Page-1", "Page-2", "Page-3", "Page-4"}, visited)
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		count := 0
+		err := client.ForEachPageWithPolicy(context.Background(), "test-token", ContinueOnError, func(Page) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+	})
+}
+
+func TestClientTopPages(t *testing.T) {
+	views := []int{5, 40, 0, 12, 40, 7, 3}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getPageList", r.URL.Path)
+		calls++
+
+		var req GetPageListRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, MaxPageListLimit, req.Limit)
+
+		// Serve three pages per call, whatever the limit, to exercise pagination
+		pages := []Page{}
+		for i := req.Offset; i < req.Offset+3 && i < len(views); i++ {
+			pages = append(pages, Page{Path: fmt.Sprintf("Page-%d", i), Views: views[i]})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageList{TotalCount: len(views), Pages: pages}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	paths := func(pages []Page) []string {
+		var result []string
+		for _, page := range pages {
+			result = append(result, page.Path)
+		}
+		return result
+	}
+
+	t.Run("ranked and truncated", func(t *testing.T) {
+		calls = 0
+		top, err := client.TopPages(context.Background(), "test-token", 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Page-1", "Page-4", "Page-3", "Page-5"}, paths(top))
+		assert.Equal(t, 40, top[0].Views)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("fewer pages than n", func(t *testing.T) {
+		top, err := client.TopPages(context.Background(), "test-token", 100)
+		require.NoError(t, err)
+		assert.Len(t, top, len(views))
+		assert.Equal(t, "Page-2", top[len(top)-1].Path)
+	})
+
+	t.Run("invalid n", func(t *testing.T) {
+		_, err := client.TopPages(context.Background(), "test-token", 0)
+		assert.EqualError(t, err, "n must be positive, got 0")
+	})
+}
@@ -0,0 +1,49 @@
+package telegraph
+
+import "strings"
+
+// telegraphSite is the public site serving pages and uploaded files
+const telegraphSite = "https://telegra.ph"
+
+// PreviewMeta is OpenGraph-style metadata for link previews of a page
+type PreviewMeta struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url,omitempty"`
+	URL         string `json:"url"`
+}
+
+// PreviewMeta returns metadata for social cards and other link previews of the page
+//
+// The page's own description and image are used when set. Otherwise the
+// description is built from the content with AutoDescription, and the image is
+// the first image in the content, if any. Relative image paths such as
+// "/file/abc.jpg" and a missing URL are resolved against telegra.ph.
+//
+// Example:
+//
+//	meta := page.PreviewMeta()
+//	fmt.Printf(`<meta property="og:description" content="%s">`, html.EscapeString(meta.Description))
+func (p Page) PreviewMeta() PreviewMeta {
+	meta := PreviewMeta{
+		Title:       p.Title,
+		Description: p.Description,
+		ImageURL:    p.ImageURL,
+		URL:         p.URL,
+	}
+	if meta.Description == "" {
+		meta.Description = AutoDescription(p.Content, DefaultDescriptionLength)
+	}
+	if meta.ImageURL == "" {
+		if images := ExtractImages(p.Content); len(images) > 0 {
+			meta.ImageURL = images[0]
+		}
+	}
+	if strings.HasPrefix(meta.ImageURL, "/") {
+		meta.ImageURL = telegraphSite + meta.ImageURL
+	}
+	if meta.URL == "" && p.Path != "" {
+		meta.URL = telegraphSite + "/" + p.Path
+	}
+	return meta
+}
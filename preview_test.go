@@ -0,0 +1,57 @@
+package telegraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagePreviewMeta(t *testing.T) {
+	content := []Node{
+		{Tag: "p", Children: []interface{}{"First paragraph of the article."}},
+		{Tag: "figure", Children: []interface{}{
+			map[string]interface{}{"tag": "img", "attrs": map[string]interface{}{"src": "/file/first.jpg"}},
+		}},
+		{Tag: "img", Attrs: map[string]string{"src": "/file/second.jpg"}},
+	}
+
+	t.Run("existing description and image", func(t *testing.T) {
+		page := Page{
+			Path:        "Article-12-15",
+			URL:         "https://telegra.ph/Article-12-15",
+			Title:       "Article",
+			Description: "Hand-written summary",
+			ImageURL:    "https://example.com/cover.png",
+			Content:     content,
+		}
+
+		assert.Equal(t, PreviewMeta{
+			Title:       "Article",
+			Description: "Hand-written summary",
+			ImageURL:    "https://example.com/cover.png",
+			URL:         "https://telegra.ph/Article-12-15",
+		}, page.PreviewMeta())
+	})
+
+	t.Run("derived from content", func(t *testing.T) {
+		page := Page{Path: "Article-12-15", Title: "Article", Content: content}
+
+		assert.Equal(t, PreviewMeta{
+			Title:       "Article",
+			Description: "First paragraph of the article.",
+			ImageURL:    "https://telegra.ph/file/first.jpg",
+			URL:         "https://telegra.ph/Article-12-15",
+		}, page.PreviewMeta())
+	})
+
+	t.Run("long content", func(t *testing.T) {
+		page := Page{Content: []Node{{Tag: "p", Children: []interface{}{strings.Repeat("word ", 100)}}}}
+
+		meta := page.PreviewMeta()
+		assert.LessOrEqual(t, len([]rune(meta.Description)), DefaultDescriptionLength)
+		assert.True(t, strings.HasSuffix(meta.Description, "word…"))
+		assert.Empty(t, meta.ImageURL)
+		assert.Empty(t, meta.URL)
+	})
+}
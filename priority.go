@@ -0,0 +1,57 @@
+package telegraph
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// priorityKey is the context key marking priority calls
+type priorityKey struct{}
+
+// Default rate limit of priority calls that find the client's rate limit exhausted
+const (
+	DefaultPriorityRateLimit rate.Limit = 2
+	DefaultPriorityBurst                = 2
+)
+
+// WithPriority returns a context that marks the calls made with it as priority calls
+//
+// Priority calls, such as health checks or token validation, do not queue
+// behind other calls waiting for the client's rate limiter. They take a token
+// from the rate limiter if one is available immediately, and otherwise wait on
+// a separate, smaller rate limit (see WithPriorityRateLimit), so that a burst
+// of priority calls still cannot flood the API.
+//
+// Example:
+//
+//	account, err := client.GetAccountInfo(telegraph.WithPriority(ctx), req)
+func WithPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, priorityKey{}, true)
+}
+
+// isPriority reports whether ctx was marked with WithPriority
+func isPriority(ctx context.Context) bool {
+	priority, _ := ctx.Value(priorityKey{}).(bool)
+	return priority
+}
+
+// WithPriorityRateLimit sets the rate limit of priority calls made while the
+// client's rate limit is exhausted (default: DefaultPriorityRateLimit per
+// second with a burst of DefaultPriorityBurst)
+func WithPriorityRateLimit(rps rate.Limit, burst int) ClientOption {
+	return func(c *Client) {
+		c.priorityLimiter = rate.NewLimiter(rps, burst)
+	}
+}
+
+// waitRateLimit blocks until the call may proceed under the client's rate limits
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if !isPriority(ctx) {
+		return c.rateLimiter.Wait(ctx)
+	}
+	if c.rateLimiter.Allow() {
+		return nil
+	}
+	return c.priorityLimiter.Wait(ctx)
+}
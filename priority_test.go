@@ -0,0 +1,66 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestClientPriorityCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Test-12-15"}})
+	}))
+	defer server.Close()
+
+	// One call per second, so queued calls wait for seconds
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1)))
+	req := &GetPageRequest{Path: "Test-12-15"}
+
+	_, err := client.GetPage(context.Background(), req)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetPage(ctx, req)
+		}()
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Let the low-priority calls queue on the rate limiter
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err = client.GetPage(WithPriority(context.Background()), req)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestClientPriorityRateLimit(t *testing.T) {
+	client := NewClient(WithRateLimit(rate.Limit(1)), WithPriorityRateLimit(rate.Limit(1), 1))
+	ctx := WithPriority(context.Background())
+
+	// The first call takes the client's token and the second the priority token
+	require.NoError(t, client.waitRateLimit(ctx))
+	require.NoError(t, client.waitRateLimit(ctx))
+
+	// Both limiters are now exhausted
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, client.waitRateLimit(timeout))
+}
@@ -0,0 +1,192 @@
+package telegraph
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// voidTags lists tags rendered without a closing tag
+var voidTags = map[string]bool{
+	"br": true, "hr": true, "img": true,
+}
+
+// urlAttrs lists the attributes rendered from content. Both hold URLs; any
+// other attribute, such as event handlers or styles, is dropped.
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+// safeURLSchemes lists the schemes of URLs rendered in href and src
+// attributes. Relative URLs are rendered too.
+var safeURLSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// ampTags maps media tags to the AMP components that replace them
+var ampTags = map[string]string{
+	"img": "amp-img", "iframe": "amp-iframe", "video": "amp-video",
+}
+
+// Default dimensions used for AMP media without width and height attributes.
+// AMP needs both to reserve space before the media loads; with the responsive
+// layout they only set the aspect ratio, and the element fills its container.
+const (
+	defaultAMPWidth  = "16"
+	defaultAMPHeight = "9"
+)
+
+// RenderHTML renders content to HTML
+//
+// Text and attribute values are escaped, attributes are written in sorted
+// order, and br, hr and img are written without closing tags. Tags not
+// supported by Telegraph are rejected. Since content may come from untrusted
+// pages, only the href and src attributes are rendered, and only with http,
+// https, mailto or relative URLs; other attributes and URLs are dropped.
+//
+// Example:
+//
+//	markup, err := telegraph.RenderHTML(page.Content)
+func RenderHTML(nodes []Node) (string, error) {
+	return renderContent(nodes, false)
+}
+
+// RenderAMP renders content to AMP HTML
+//
+// It behaves like RenderHTML, except that img, iframe and video become
+// amp-img, amp-iframe and amp-video with the responsive layout. Numeric width
+// and height attributes are kept; otherwise both default to a 16:9 aspect
+// ratio. amp-iframe is always sandboxed to allow scripts and same-origin
+// access, as most embeds need.
+//
+// Example:
+//
+//	markup, err := telegraph.RenderAMP(page.Content)
+func RenderAMP(nodes []Node) (string, error) {
+	return renderContent(nodes, true)
+}
+
+// renderContent renders nodes to HTML, or to AMP HTML if amp is set
+func renderContent(nodes []Node, amp bool) (string, error) {
+	var b strings.Builder
+	for i, node := range nodes {
+		if err := renderNode(&b, node, fmt.Sprintf("content[%d]", i), amp, 1); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// renderNode writes node and its children to b
+func renderNode(b *strings.Builder, node Node, path string, amp bool, depth int) error {
+	if depth > DefaultMaxDepth {
+		return fmt.Errorf("%s: content exceeds maximum nesting depth of %d", path, DefaultMaxDepth)
+	}
+
+	// Text nodes are written as escaped text
+	if node.Tag == "" {
+		b.WriteString(html.EscapeString(node.Content))
+		return nil
+	}
+
+	tag := strings.ToLower(node.Tag)
+	if !supportedTags[tag] {
+		return fmt.Errorf("%s: unsupported tag <%s>", path, node.Tag)
+	}
+
+	attrs := safeAttrs(node.Attrs)
+	name := tag
+	if ampTag, ok := ampTags[tag]; ok && amp {
+		name = ampTag
+		attrs = ampAttrs(tag, attrs, node.Attrs)
+	}
+
+	b.WriteString("<" + name)
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.WriteString(" " + key + `="` + html.EscapeString(attrs[key]) + `"`)
+	}
+	b.WriteString(">")
+
+	// AMP components always need a closing tag
+	if voidTags[tag] && name == tag {
+		return nil
+	}
+
+	b.WriteString(html.EscapeString(node.Content))
+	for i, child := range node.Children {
+		if text, ok := child.(string); ok {
+			b.WriteString(html.EscapeString(text))
+			continue
+		}
+		childNode, ok := asNode(child)
+		if !ok {
+			continue
+		}
+		childPath := fmt.Sprintf("%s.children[%d]", path, i)
+		if err := renderNode(b, childNode, childPath, amp, depth+1); err != nil {
+			return err
+		}
+	}
+
+	b.WriteString("</" + name + ">")
+	return nil
+}
+
+// safeAttrs returns the attributes of attrs that are safe to render
+func safeAttrs(attrs map[string]string) map[string]string {
+	result := make(map[string]string, len(attrs))
+	for key, value := range attrs {
+		key = strings.ToLower(key)
+		if urlAttrs[key] && isSafeURL(value) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// isSafeURL reports whether rawURL is relative or uses one of safeURLSchemes
+func isSafeURL(rawURL string) bool {
+	// Browsers ignore leading whitespace and control characters
+	trimmed := strings.TrimLeftFunc(rawURL, func(r rune) bool { return r <= ' ' })
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || safeURLSchemes[strings.ToLower(u.Scheme)]
+}
+
+// ampAttrs returns the attributes of the AMP component replacing a media tag:
+// the safe attributes in attrs, and the dimensions in original if numeric,
+// along with the layout attributes the component needs
+func ampAttrs(tag string, attrs, original map[string]string) map[string]string {
+	result := make(map[string]string, len(attrs)+5)
+	for key, value := range attrs {
+		result[key] = value
+	}
+	width, height := original["width"], original["height"]
+	if isDimension(width) && isDimension(height) {
+		result["width"], result["height"] = width, height
+	} else {
+		result["width"] = defaultAMPWidth
+		result["height"] = defaultAMPHeight
+	}
+	result["layout"] = "responsive"
+
+	switch tag {
+	case "iframe":
+		result["sandbox"] = "allow-scripts allow-same-origin"
+	case "video":
+		result["controls"] = ""
+	}
+	return result
+}
+
+// isDimension reports whether s is a positive number of pixels
+func isDimension(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0
+}
@@ -0,0 +1,135 @@
+package telegraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	content := []Node{
+		{Tag: "p", Children: []interface{}{
+			"Fish & chips ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com/?a=1&b=2"}, Children: []interface{}{"here"}},
+			Node{Tag: "br"},
+		}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+			map[string]interface{}{"tag": "figcaption", "children": []interface{}{"<caption>"}},
+		}},
+	}
+
+	markup, err := RenderHTML(content)
+	require.NoError(t, err)
+	assert.Equal(t, `<p>Fish &amp; chips <a href="https://example.com/?a=1&amp;b=2">here</a><br></p>`+
+		`<figure><img src="/file/image.jpg"><figcaption>&lt;caption&gt;</figcaption></figure>`, markup)
+
+	_, err = RenderHTML([]Node{{Tag: "p", Children: []interface{}{Node{Tag: "script"}}}})
+	assert.EqualError(t, err, "content[0].children[0]: unsupported tag <script>")
+}
+
+func TestRenderHTMLUnsafeAttrs(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{
+			name: "event handlers and styles",
+			node: Node{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg", "onerror": "alert(1)", "style": "x", "OnClick": "alert(1)"}},
+			want: `<img src="/file/image.jpg">`,
+		},
+		{
+			name: "keys that are not attribute names",
+			node: Node{Tag: "a", Attrs: map[string]string{"href": "/page", `onmouseover="alert(1)" a`: "x", "x><script>alert(1)</script": "y"}, Children: []interface{}{"link"}},
+			want: `<a href="/page">link</a>`,
+		},
+		{
+			name: "javascript URL",
+			node: Node{Tag: "a", Attrs: map[string]string{"href": "javascript:alert(1)"}, Children: []interface{}{"link"}},
+			want: `<a>link</a>`,
+		},
+		{
+			name: "obfuscated javascript URL",
+			node: Node{Tag: "a", Attrs: map[string]string{"href": " \tJavaScript:alert(1)"}, Children: []interface{}{"link"}},
+			want: `<a>link</a>`,
+		},
+		{
+			name: "data URL",
+			node: Node{Tag: "iframe", Attrs: map[string]string{"src": "data:text/html,<script>alert(1)</script>"}},
+			want: `<iframe></iframe>`,
+		},
+		{
+			name: "safe URLs",
+			node: Node{Tag: "p", Children: []interface{}{
+				Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com/?a=1&b=2"}},
+				Node{Tag: "a", Attrs: map[string]string{"href": "mailto:jane@example.com"}},
+				Node{Tag: "a", Attrs: map[string]string{"href": "#Notes"}},
+				Node{Tag: "a", Attrs: map[string]string{"href": "//example.com/page"}},
+			}},
+			want: `<p><a href="https://example.com/?a=1&amp;b=2"></a><a href="mailto:jane@example.com"></a>` +
+				`<a href="#Notes"></a><a href="//example.com/page"></a></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markup, err := RenderHTML([]Node{tt.node})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, markup)
+		})
+	}
+
+	t.Run("AMP", func(t *testing.T) {
+		markup, err := RenderAMP([]Node{{Tag: "iframe", Attrs: map[string]string{
+			"src":     "javascript:alert(1)",
+			"sandbox": "allow-top-navigation",
+			"width":   `1" onload="alert(1)`,
+			"height":  "480",
+			"onload":  "alert(1)",
+		}}})
+		require.NoError(t, err)
+		assert.Equal(t, `<amp-iframe height="9" layout="responsive" sandbox="allow-scripts allow-same-origin" width="16"></amp-iframe>`, markup)
+	})
+}
+
+func TestRenderAMP(t *testing.T) {
+	t.Run("image", func(t *testing.T) {
+		markup, err := RenderAMP([]Node{{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+		}}})
+		require.NoError(t, err)
+		assert.Equal(t, `<figure><amp-img height="9" layout="responsive" src="/file/image.jpg" width="16"></amp-img></figure>`, markup)
+	})
+
+	t.Run("image with dimensions", func(t *testing.T) {
+		markup, err := RenderAMP([]Node{{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg", "width": "640", "height": "480"}}})
+		require.NoError(t, err)
+		assert.Equal(t, `<amp-img height="480" layout="responsive" src="/file/image.jpg" width="640"></amp-img>`, markup)
+	})
+
+	t.Run("iframe", func(t *testing.T) {
+		markup, err := RenderAMP([]Node{{Tag: "iframe", Attrs: map[string]string{"src": "https://www.youtube.com/embed/abc"}}})
+		require.NoError(t, err)
+		assert.Equal(t, `<amp-iframe height="9" layout="responsive" sandbox="allow-scripts allow-same-origin" `+
+			`src="https://www.youtube.com/embed/abc" width="16"></amp-iframe>`, markup)
+	})
+
+	t.Run("video", func(t *testing.T) {
+		markup, err := RenderAMP([]Node{{Tag: "video", Attrs: map[string]string{"src": "/file/video.mp4"}}})
+		require.NoError(t, err)
+		assert.Equal(t, `<amp-video controls="" height="9" layout="responsive" src="/file/video.mp4" width="16"></amp-video>`, markup)
+	})
+
+	t.Run("other tags unchanged", func(t *testing.T) {
+		content := []Node{{Tag: "p", Children: []interface{}{Node{Tag: "b", Children: []interface{}{"bold"}}}}, {Tag: "hr"}}
+
+		amp, err := RenderAMP(content)
+		require.NoError(t, err)
+		plain, err := RenderHTML(content)
+		require.NoError(t, err)
+		assert.Equal(t, plain, amp)
+		assert.NotContains(t, amp, "<img")
+	})
+}
@@ -0,0 +1,109 @@
+package telegraph
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRSSDescriptionLength is the maximum number of text characters in the
+// descriptions rendered by RenderRSSItem
+const DefaultRSSDescriptionLength = 500
+
+// RSSItemOptions represents optional behavior of RenderRSSItemWithOptions
+type RSSItemOptions struct {
+	// MaxLength is the maximum number of text characters of the description,
+	// not counting markup, or 0 for no limit
+	MaxLength int
+}
+
+// RenderRSSItem returns the title, link and description of an RSS item for a page
+//
+// The description is the page's content rendered with RenderHTML, truncated to
+// DefaultRSSDescriptionLength characters of text and wrapped in a CDATA
+// section, so it can be written into the item's description element as-is.
+// Use RenderRSSItemWithOptions to change the length. The page must have been
+// fetched with its content.
+//
+// Example:
+//
+//	title, link, description, err := telegraph.RenderRSSItem(page)
+func RenderRSSItem(p *Page) (title, link, descriptionHTML string, err error) {
+	return RenderRSSItemWithOptions(p, RSSItemOptions{MaxLength: DefaultRSSDescriptionLength})
+}
+
+// RenderRSSItemWithOptions is like RenderRSSItem but applies opts
+//
+// Content longer than MaxLength is cut at a word boundary where possible and
+// marked with an ellipsis. Elements are cut rather than dropped, so the
+// description stays well-formed HTML.
+func RenderRSSItemWithOptions(p *Page, opts RSSItemOptions) (title, link, descriptionHTML string, err error) {
+	content := p.Content
+	if opts.MaxLength > 0 {
+		budget := opts.MaxLength
+		children := make([]interface{}, len(content))
+		for i, node := range content {
+			children[i] = normalizeNode(node)
+		}
+
+		content = nil
+		for _, child := range truncateChildren(children, &budget) {
+			content = append(content, child.(Node))
+		}
+	}
+
+	markup, err := RenderHTML(content)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	link = p.URL
+	if link == "" {
+		link = telegraphSite + "/" + p.Path
+	}
+	return p.Title, link, cdata(markup), nil
+}
+
+// truncateChildren returns the normalized children cut to the remaining budget
+// of text characters, which it decreases by the text kept
+func truncateChildren(children []interface{}, budget *int) []interface{} {
+	var result []interface{}
+	for _, child := range children {
+		if *budget <= 0 {
+			break
+		}
+
+		node, isNode := child.(Node)
+		text, isText := child.(string)
+		if isNode && node.Tag == "" {
+			text, isText = node.Content, true
+		}
+
+		switch {
+		case isText:
+			if n := utf8.RuneCountInString(text); n > *budget {
+				text = truncateWords(text, *budget)
+				*budget = 0
+			} else {
+				*budget -= n
+			}
+			if isNode {
+				node.Content = text
+				child = node
+			} else {
+				child = text
+			}
+		case isNode:
+			if node.Children != nil {
+				node.Children = truncateChildren(node.Children, budget)
+			}
+			child = node
+		}
+		result = append(result, child)
+	}
+	return result
+}
+
+// cdata wraps s in a CDATA section, splitting any "]]>" in s across sections
+func cdata(s string) string {
+	return "<![CDATA[" + strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
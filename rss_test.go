@@ -0,0 +1,54 @@
+package telegraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRSSItem(t *testing.T) {
+	page := &Page{
+		Path:  "Article-12-15",
+		Title: "Article",
+		Content: []Node{
+			{Tag: "p", Children: []interface{}{"The quick ", Node{Tag: "strong", Children: []interface{}{"brown fox jumps"}}, " over the lazy dog."}},
+			{Tag: "p", Children: []interface{}{"Second paragraph."}},
+		},
+	}
+
+	t.Run("truncated at a word boundary", func(t *testing.T) {
+		title, link, description, err := RenderRSSItemWithOptions(page, RSSItemOptions{MaxLength: 20})
+		require.NoError(t, err)
+		assert.Equal(t, "Article", title)
+		assert.Equal(t, "https://telegra.ph/Article-12-15", link)
+
+		// The cut falls inside strong, which is still closed
+		assert.Equal(t, "<![CDATA[<p>The quick <strong>brown fox…</strong></p>]]>", description)
+	})
+
+	t.Run("short content kept whole", func(t *testing.T) {
+		page := *page
+		page.URL = "https://telegra.ph/Article-12-15-2"
+
+		_, link, description, err := RenderRSSItem(&page)
+		require.NoError(t, err)
+		assert.Equal(t, "https://telegra.ph/Article-12-15-2", link)
+		assert.Equal(t, "<![CDATA[<p>The quick <strong>brown fox jumps</strong> over the lazy dog.</p><p>Second paragraph.</p>]]>", description)
+	})
+
+	t.Run("CDATA terminator in content", func(t *testing.T) {
+		page := &Page{Path: "X", Content: []Node{{Tag: "img", Attrs: map[string]string{"src": "/file/a]]>b.jpg"}}}}
+
+		_, _, description, err := RenderRSSItem(page)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(description, "<![CDATA["))
+		assert.True(t, strings.HasSuffix(description, "]]>"))
+	})
+
+	t.Run("unsupported content", func(t *testing.T) {
+		_, _, _, err := RenderRSSItem(&Page{Content: []Node{{Tag: "script"}}})
+		assert.Error(t, err)
+	})
+}
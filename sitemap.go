@@ -0,0 +1,58 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+)
+
+// sitemapNamespace is the XML namespace of sitemaps
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapURLSet is the root element of a sitemap
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is a single page of a sitemap
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// GenerateSitemap returns an XML sitemap listing the URL of every page of the account
+//
+// Pages are fetched MaxPageListLimit at a time, subject to the client's rate
+// limit, and listed in the order of the page list. The sitemap has no lastmod
+// dates, as the API does not report when a page was last edited and page paths
+// only hold the month and day it was created. Sitemaps are limited to 50,000
+// URLs, so larger accounts need to be split by the caller.
+//
+// Example:
+//
+//	sitemap, err := client.GenerateSitemap(ctx, token)
+//	if err == nil {
+//		err = os.WriteFile("sitemap.xml", sitemap, 0o644)
+//	}
+func (c *Client) GenerateSitemap(ctx context.Context, accessToken string) ([]byte, error) {
+	pages, err := c.GetAllPages(ctx, &GetPageListRequest{AccessToken: accessToken, Limit: MaxPageListLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	urlSet := sitemapURLSet{Xmlns: sitemapNamespace, URLs: make([]sitemapURL, 0, len(pages))}
+	for _, page := range pages {
+		loc := page.URL
+		if loc == "" {
+			loc = telegraphSite + "/" + page.Path
+		}
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{Loc: loc})
+	}
+
+	data, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
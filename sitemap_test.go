@@ -0,0 +1,70 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGenerateSitemap(t *testing.T) {
+	const total = 5
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getPageList", r.URL.Path)
+		calls++
+
+		var req GetPageListRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// Serve two pages per call to exercise pagination
+		pages := []Page{}
+		for i := req.Offset; i < req.Offset+2 && i < total; i++ {
+			page := Page{Path: fmt.Sprintf("Page-%d-12-15", i)}
+			if i != 3 {
+				page.URL = "https://telegra.ph/" + page.Path
+			}
+			pages = append(pages, page)
+		}
+
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageList{TotalCount: total, Pages: pages}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	t.Run("all pages listed", func(t *testing.T) {
+		sitemap, err := client.GenerateSitemap(context.Background(), "test-token")
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.True(t, strings.HasPrefix(string(sitemap), xml.Header))
+		assert.Contains(t, string(sitemap), `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+
+		var parsed struct {
+			URLs []struct {
+				Loc string `xml:"loc"`
+			} `xml:"url"`
+		}
+		require.NoError(t, xml.Unmarshal(sitemap, &parsed))
+		require.Len(t, parsed.URLs, total)
+		for i, u := range parsed.URLs {
+			assert.Equal(t, fmt.Sprintf("https://telegra.ph/Page-%d-12-15", i), u.Loc)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		sitemap, err := client.GenerateSitemap(ctx, "test-token")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, sitemap)
+	})
+}
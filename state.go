@@ -0,0 +1,124 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// clientState is the client state persisted by SaveState
+type clientState struct {
+	AccessToken string `json:"access_token,omitempty"`
+	// DefaultAuthor is the author cached by SetDefaultAuthorFromAccount
+	DefaultAuthor *Account `json:"default_author,omitempty"`
+}
+
+// WithAccessToken stores an access token in the client, see SetAccessToken
+func WithAccessToken(token string) ClientOption {
+	return func(c *Client) {
+		c.accessToken = token
+	}
+}
+
+// SetAccessToken stores an access token in the client so it can be persisted with
+// SaveState. Requests are not changed; pass AccessToken() in them as usual.
+func (c *Client) SetAccessToken(token string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.accessToken = token
+}
+
+// AccessToken returns the access token stored in the client, if any
+func (c *Client) AccessToken() string {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+
+	return c.accessToken
+}
+
+// SaveState writes the stored access token and the cached default author to a
+// JSON file at path, readable and writable by the owner only (0600). The file is
+// replaced atomically if it exists.
+//
+// The access token is written in plain text, and anyone who can read the file
+// can edit the account's pages. Keep it out of shared or version-controlled directories.
+//
+// Example:
+//
+//	client.SetAccessToken(account.AccessToken)
+//	if err := client.SaveState(filepath.Join(configDir, "telegraph.json")); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *Client) SaveState(path string) error {
+	c.stateMu.RLock()
+	state := clientState{AccessToken: c.accessToken}
+	if c.defaultAuthor != nil {
+		author := *c.defaultAuthor
+		state.DefaultAuthor = &author
+	}
+	c.stateMu.RUnlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal client state: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save client state: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so that path never holds partially written data
+func writeFileAtomic(path string, data []byte) error {
+	// CreateTemp creates the file with 0600 permissions
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Flush to disk before the rename, so a crash cannot leave an empty file at path
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadState restores the access token and cached default author saved by SaveState
+//
+// Example:
+//
+//	if err := client.LoadState(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+//		log.Fatal(err)
+//	}
+//	token := client.AccessToken()
+func (c *Client) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load client state: %w", err)
+	}
+
+	var state clientState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse client state: %w", err)
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.accessToken = state.AccessToken
+	c.defaultAuthor = state.DefaultAuthor
+	return nil
+}
@@ -0,0 +1,111 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{AuthorName: "Jane Doe", AuthorURL: "https://example.com/jane"}})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	client := NewClient(WithBaseURL(server.URL), WithAccessToken("test-token"))
+	require.NoError(t, client.SetDefaultAuthorFromAccount(context.Background(), client.AccessToken()))
+	require.NoError(t, client.SaveState(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded := NewClient()
+	require.NoError(t, loaded.LoadState(path))
+	assert.Equal(t, "test-token", loaded.AccessToken())
+	name, authorURL := loaded.defaultAuthorFields()
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "https://example.com/jane", authorURL)
+
+	t.Run("overwrite", func(t *testing.T) {
+		loaded.SetAccessToken("new-token")
+		require.NoError(t, loaded.SaveState(path))
+
+		again := NewClient()
+		require.NoError(t, again.LoadState(path))
+		assert.Equal(t, "new-token", again.AccessToken())
+
+		entries, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := NewClient().LoadState(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestClientStateDuringCalls(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Account{ShortName: "test"}})
+	}))
+	defer server.Close()
+
+	var client *Client
+	hookTokens := make(chan string, 2)
+	client = NewClient(
+		WithBaseURL(server.URL),
+		WithAccessToken("old-token"),
+		WithRequestHook(func(req *http.Request, attempt int) {
+			hookTokens <- client.AccessToken()
+		}),
+	)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetAccountInfo(context.Background(), &GetAccountInfoRequest{AccessToken: "old-token"})
+		done <- err
+	}()
+	<-received
+
+	// Rotating the token does not wait for the call in flight
+	rotated := make(chan struct{})
+	go func() {
+		client.SetAccessToken("new-token")
+		close(rotated)
+	}()
+	select {
+	case <-rotated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetAccessToken blocked behind a call in flight")
+	}
+
+	// New calls, including their hooks, proceed while the first one is in flight
+	go func() {
+		_, err := client.GetAccountInfo(context.Background(), &GetAccountInfoRequest{AccessToken: "new-token"})
+		done <- err
+	}()
+	<-received
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+	assert.Equal(t, "old-token", <-hookTokens)
+	assert.Equal(t, "new-token", <-hookTokens)
+}
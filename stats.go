@@ -0,0 +1,70 @@
+package telegraph
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// ClientStats holds cumulative counters of the API calls made by a client
+type ClientStats struct {
+	// Requests is the number of API calls, whether they succeeded or not
+	Requests int64
+	// Retries is the number of attempts made after the first attempt of a call
+	Retries int64
+	// RateLimitWait is the total time calls spent waiting for the client's rate limiter
+	RateLimitWait time.Duration
+	// Endpoints is the number of calls per API method name, e.g. "createPage"
+	Endpoints map[string]int64
+}
+
+// clientStats accumulates the ClientStats of a client
+type clientStats struct {
+	mu    sync.Mutex
+	stats ClientStats
+}
+
+// record adds a finished call to the stats
+func (s *clientStats) record(metrics RequestMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Requests++
+	s.stats.Retries += int64(metrics.Retries)
+	s.stats.RateLimitWait += metrics.RateLimitWait
+	if s.stats.Endpoints == nil {
+		s.stats.Endpoints = make(map[string]int64)
+	}
+	s.stats.Endpoints[metrics.Endpoint]++
+}
+
+// Stats returns the counters of the API calls made by the client since it was
+// created or ResetStats was last called
+//
+// The counters are read together, so they are consistent with each other even
+// while calls are in flight. Calls are counted once they finish. Uploads are
+// not API calls and are not counted.
+//
+// Example:
+//
+//	stats := client.Stats()
+//	log.Printf("%d calls, %d retries, %s rate limited", stats.Requests, stats.Retries, stats.RateLimitWait)
+func (c *Client) Stats() ClientStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	stats := c.stats.stats
+	stats.Endpoints = maps.Clone(stats.Endpoints)
+	if stats.Endpoints == nil {
+		stats.Endpoints = make(map[string]int64)
+	}
+	return stats
+}
+
+// ResetStats sets the counters returned by Stats back to zero
+func (c *Client) ResetStats() {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	c.stats.stats = ClientStats{}
+}
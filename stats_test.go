@@ -0,0 +1,90 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestClientStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	failures := 0
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(20)),
+		WithRetryConfig(RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}),
+		WithFaultInjector(func(endpoint string) error {
+			if failures > 0 {
+				failures--
+				return InjectStatus(http.StatusServiceUnavailable)
+			}
+			return nil
+		}),
+	)
+	ctx := context.Background()
+
+	assert.Equal(t, ClientStats{Endpoints: map[string]int64{}}, client.Stats())
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetViews(ctx, &GetViewsRequest{Path: "Test-12-15"})
+		require.NoError(t, err)
+	}
+	failures = 2
+	_, err := client.GetPage(ctx, &GetPageRequest{Path: "Test-12-15"})
+	require.NoError(t, err)
+	failures = 10
+	_, err = client.GetPageList(ctx, &GetPageListRequest{AccessToken: "test-token"})
+	require.Error(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(5), stats.Requests)
+	assert.Equal(t, int64(5), stats.Retries)
+	assert.Equal(t, map[string]int64{"getViews": 3, "getPage": 1, "getPageList": 1}, stats.Endpoints)
+
+	// Five calls against a burst of 20 never wait for long
+	assert.Less(t, stats.RateLimitWait, 100*time.Millisecond)
+
+	// The returned map is a copy
+	stats.Endpoints["getViews"] = 100
+	assert.Equal(t, int64(3), client.Stats().Endpoints["getViews"])
+
+	client.ResetStats()
+	assert.Equal(t, ClientStats{Endpoints: map[string]int64{}}, client.Stats())
+}
+
+func TestClientStatsRateLimitWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 1}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithSharedRateLimiter(rate.NewLimiter(rate.Every(50*time.Millisecond), 1)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetViews(context.Background(), &GetViewsRequest{Path: "Test-12-15"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// The calls wait 0, 50, 100 and 150ms
+	stats := client.Stats()
+	assert.Equal(t, int64(4), stats.Requests)
+	assert.GreaterOrEqual(t, stats.RateLimitWait, 250*time.Millisecond)
+}
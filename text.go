@@ -0,0 +1,134 @@
+package telegraph
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// blockEnd marks the end of a block element while extracting text
+type blockEnd struct{}
+
+// PlainText returns the text of content without any markup
+//
+// Block elements such as p, h3, li, aside, figure and figcaption are placed on
+// their own lines, and br starts a new line. Images, videos and embeds have no
+// text and are skipped, so a figure contributes only its caption.
+//
+// Example:
+//
+//	text := telegraph.PlainText(page.Content)
+func PlainText(nodes []Node) string {
+	var result strings.Builder
+	newline := func() {
+		if result.Len() > 0 && !strings.HasSuffix(result.String(), "\n") {
+			result.WriteString("\n")
+		}
+	}
+
+	stack := make([]interface{}, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		stack = append(stack, nodes[i])
+	}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if text, ok := top.(string); ok {
+			result.WriteString(text)
+			continue
+		}
+		if _, ok := top.(blockEnd); ok {
+			newline()
+			continue
+		}
+
+		node, ok := asNode(top)
+		if !ok {
+			continue
+		}
+
+		tag := strings.ToLower(node.Tag)
+		switch {
+		case mediaTags[tag]:
+			continue
+		case tag == "br":
+			result.WriteString("\n")
+			continue
+		case blockTags[tag]:
+			newline()
+			stack = append(stack, blockEnd{})
+		}
+
+		result.WriteString(node.Content)
+		for i := len(node.Children) - 1; i >= 0; i-- {
+			stack = append(stack, node.Children[i])
+		}
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// DefaultDescriptionLength is the maximum length in characters of descriptions
+// built by PreviewMeta
+const DefaultDescriptionLength = 200
+
+// AutoDescription returns a short description of content for use in link previews
+//
+// The plain text of content (see PlainText) is collapsed to a single line and
+// cut to at most maxLen characters, at a word boundary where possible, with an
+// ellipsis marking the cut. A maxLen of zero or less means no limit.
+//
+// Example:
+//
+//	description := telegraph.AutoDescription(page.Content, telegraph.DefaultDescriptionLength)
+func AutoDescription(nodes []Node, maxLen int) string {
+	text := strings.Join(strings.Fields(PlainText(nodes)), " ")
+	if maxLen <= 0 || utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+	return truncateWords(text, maxLen)
+}
+
+// truncateWords cuts text, which is longer than maxLen characters, to at most
+// maxLen characters ending with an ellipsis, at the end of the last word that
+// fits if there is one
+func truncateWords(text string, maxLen int) string {
+	runes := []rune(text)
+	n := max(maxLen-1, 0)
+	cut := string(runes[:n])
+	if !unicode.IsSpace(runes[n]) {
+		// The cut is inside a word, so move it back to the start of the word
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",.;:", r)
+	}) + "…"
+}
+
+// SafeText returns a text node holding user-provided text
+//
+// Text nodes are never parsed, so markup in s is treated literally: "<b>" is
+// shown as those three characters, and RenderHTML escapes it. SafeText also
+// normalizes line endings to "\n", replaces invalid UTF-8 with U+FFFD, and
+// strips control characters other than newlines and tabs, which would otherwise
+// be carried into exported HTML or Markdown.
+//
+// Example:
+//
+//	content := []telegraph.Node{{Tag: "p", Children: []interface{}{telegraph.SafeText(message.Text)}}}
+func SafeText(s string) Node {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return Node{Content: s}
+}
@@ -0,0 +1,94 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainText(t *testing.T) {
+	t.Run("inline markup", func(t *testing.T) {
+		content := []Node{
+			{Tag: "h3", Children: []interface{}{"Title"}},
+			{Tag: "p", Children: []interface{}{"Hello, ", Node{Tag: "strong", Children: []interface{}{"world"}}, "!"}},
+			{Tag: "p", Children: []interface{}{"Line one", Node{Tag: "br"}, "Line two"}},
+			{Tag: "ul", Children: []interface{}{
+				Node{Tag: "li", Children: []interface{}{"First"}},
+				Node{Tag: "li", Children: []interface{}{"Second"}},
+			}},
+		}
+		assert.Equal(t, "Title\nHello, world!\nLine one\nLine two\nFirst\nSecond", PlainText(content))
+	})
+
+	t.Run("figure with caption", func(t *testing.T) {
+		content := []Node{
+			{Tag: "p", Children: []interface{}{"Before"}},
+			{Tag: "figure", Children: []interface{}{
+				Node{Tag: "img", Attrs: map[string]string{"src": "/file/abc.jpg"}},
+				Node{Tag: "figcaption", Children: []interface{}{"A ", Node{Tag: "em", Children: []interface{}{"lovely"}}, " view"}},
+			}},
+			{Tag: "p", Children: []interface{}{"After"}},
+		}
+		assert.Equal(t, "Before\nA lovely view\nAfter", PlainText(content))
+	})
+
+	t.Run("aside", func(t *testing.T) {
+		content := []Node{
+			{Tag: "p", Children: []interface{}{"Main text"}},
+			{Tag: "aside", Children: []interface{}{"Side note"}},
+			{Tag: "p", Children: []interface{}{"More text"}},
+		}
+		assert.Equal(t, "Main text\nSide note\nMore text", PlainText(content))
+	})
+
+	t.Run("decoded content", func(t *testing.T) {
+		var content []Node
+		require.NoError(t, json.Unmarshal([]byte(`[{"tag":"figure","children":[{"tag":"img","attrs":{"src":"/file/abc.jpg"}},{"tag":"figcaption","children":["Caption"]}]},{"tag":"aside","children":["Aside"]}]`), &content))
+		assert.Equal(t, "Caption\nAside", PlainText(content))
+	})
+}
+
+func TestAutoDescription(t *testing.T) {
+	content := []Node{
+		{Tag: "h3", Children: []interface{}{"Intro"}},
+		{Tag: "p", Children: []interface{}{"The quick brown fox jumps over the lazy dog."}},
+	}
+
+	assert.Equal(t, "Intro The quick brown fox jumps over the lazy dog.", AutoDescription(content, 0))
+	assert.Equal(t, "Intro The quick brown fox jumps over the lazy dog.", AutoDescription(content, 100))
+	assert.Equal(t, "Intro The quick…", AutoDescription(content, 20))
+	assert.Equal(t, "Intro…", AutoDescription(content, 7))
+
+	// Cuts at a character, not byte, count
+	assert.Equal(t, "Привет…", AutoDescription([]Node{{Tag: "p", Children: []interface{}{"Привет, мир"}}}, 10))
+	assert.Empty(t, AutoDescription(nil, 10))
+}
+
+func TestSafeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Hello, world", "Hello, world"},
+		{"markup kept literally", "<b>bold</b> & <script>alert(1)</script>", "<b>bold</b> & <script>alert(1)</script>"},
+		{"control characters stripped", "a\x00b\x07c\x1bd\u0085e\x7f", "abcde"},
+		{"newlines and tabs kept", "line 1\n\tline 2", "line 1\n\tline 2"},
+		{"line endings normalized", "a\r\nb\rc", "a\nb\nc"},
+		{"invalid UTF-8 replaced", "a\xffb", "a�b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := SafeText(tt.in)
+			assert.Equal(t, Node{Content: tt.want}, node)
+		})
+	}
+
+	// Markup survives rendering as text rather than elements
+	markup, err := RenderHTML([]Node{{Tag: "p", Children: []interface{}{SafeText("<i>x</i>")}}})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>&lt;i&gt;x&lt;/i&gt;</p>", markup)
+}
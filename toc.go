@@ -0,0 +1,70 @@
+package telegraph
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// anchorSeparators matches the runs of characters replaced by a dash in heading anchors
+var anchorSeparators = regexp.MustCompile(`[\s#?&/]+`)
+
+// HeadingAnchor returns the anchor Telegraph gives a heading with the given text,
+// without the leading "#"
+//
+// Surrounding whitespace is trimmed, and runs of whitespace and characters that
+// are special in URLs are replaced with a single dash, so "Getting started" has
+// the anchor "Getting-started".
+func HeadingAnchor(text string) string {
+	return strings.Trim(anchorSeparators.ReplaceAllString(strings.TrimSpace(text), "-"), "-")
+}
+
+// GenerateTOC builds a table of contents from the h3 and h4 headings of content
+//
+// The result is a single ul with one li per heading, in document order, each
+// holding a link to the heading's anchor. Headings without text are skipped.
+// Headings with the same anchor are told apart by a numeric suffix, so the
+// second "Notes" heading links to "#Notes-2". GenerateTOC returns nil if
+// content has no headings.
+//
+// Example:
+//
+//	content = append(telegraph.GenerateTOC(content), content...)
+func GenerateTOC(nodes []Node) []Node {
+	var items []interface{}
+	used := make(map[string]bool)
+
+	for _, node := range nodes {
+		tag := strings.ToLower(node.Tag)
+		if tag != "h3" && tag != "h4" {
+			continue
+		}
+		text := strings.TrimSpace(PlainText([]Node{node}))
+		anchor := HeadingAnchor(text)
+		if anchor == "" {
+			continue
+		}
+
+		unique := anchor
+		for n := 2; used[unique]; n++ {
+			unique = anchor + "-" + strconv.Itoa(n)
+		}
+		used[unique] = true
+
+		items = append(items, Node{
+			Tag: "li",
+			Children: []interface{}{
+				Node{
+					Tag:      "a",
+					Attrs:    map[string]string{"href": "#" + unique},
+					Children: []interface{}{Node{Content: text}},
+				},
+			},
+		})
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+	return []Node{{Tag: "ul", Children: items}}
+}
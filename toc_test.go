@@ -0,0 +1,46 @@
+package telegraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadingAnchor(t *testing.T) {
+	assert.Equal(t, "Getting-started", HeadingAnchor("  Getting started "))
+	assert.Equal(t, "Q-A", HeadingAnchor("Q & A"))
+	assert.Equal(t, "Привет-мир", HeadingAnchor("Привет мир"))
+	assert.Equal(t, "", HeadingAnchor(" # "))
+}
+
+func TestGenerateTOC(t *testing.T) {
+	content := NewContentBuilder().
+		AddHeading("Introduction", 3).
+		AddParagraph("Text").
+		AddHeading("Notes", 4).
+		AddHeading("Usage", 3).
+		AddHeading("Notes", 4).
+		AddHeading("Notes-2", 4).
+		AddHeading("  ", 4).
+		Build()
+
+	toc := GenerateTOC(content)
+	require.Len(t, toc, 1)
+	assert.Equal(t, "ul", toc[0].Tag)
+
+	var hrefs, texts []string
+	for _, child := range toc[0].Children {
+		li := child.(Node)
+		assert.Equal(t, "li", li.Tag)
+		link := li.Children[0].(Node)
+		assert.Equal(t, "a", link.Tag)
+		hrefs = append(hrefs, link.Attrs["href"])
+		texts = append(texts, PlainText([]Node{link}))
+	}
+	assert.Equal(t, []string{"#Introduction", "#Notes", "#Usage", "#Notes-2", "#Notes-2-2"}, hrefs)
+	assert.Equal(t, []string{"Introduction", "Notes", "Usage", "Notes", "Notes-2"}, texts)
+	assert.NoError(t, ValidateContent(toc))
+
+	assert.Nil(t, GenerateTOC(NewContentBuilder().AddParagraph("No headings").Build()))
+}
@@ -1,9 +1,21 @@
 package telegraph
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/idna"
 )
 
 // APIResponse represents the base response structure from the Telegraph API
@@ -17,6 +29,84 @@ type APIResponse struct {
 type APIError struct {
 	Code        int    `json:"error_code,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Kind is the classified form of Description, populated by the client
+	Kind ErrorKind `json:"-"`
+}
+
+// ErrorKind classifies the error descriptions returned by the Telegraph API
+type ErrorKind int
+
+const (
+	// ErrorKindUnknown is used for descriptions that are not recognized
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindAccessTokenInvalid
+	ErrorKindShortNameRequired
+	ErrorKindTitleRequired
+	ErrorKindTitleTooLong
+	ErrorKindContentRequired
+	ErrorKindContentTooBig
+	ErrorKindContentFormatInvalid
+	ErrorKindAuthorNameTooLong
+	ErrorKindAuthorURLTooLong
+	ErrorKindPageNotFound
+	ErrorKindPageAccessDenied
+	ErrorKindPageSaveFailed
+	// ErrorKindFloodWait covers the FLOOD_WAIT_X family of rate limit errors
+	ErrorKindFloodWait
+)
+
+// errorKinds maps Telegraph error descriptions to their ErrorKind
+var errorKinds = map[string]ErrorKind{
+	"ACCESS_TOKEN_INVALID":   ErrorKindAccessTokenInvalid,
+	"SHORT_NAME_REQUIRED":    ErrorKindShortNameRequired,
+	"TITLE_REQUIRED":         ErrorKindTitleRequired,
+	"TITLE_TOO_LONG":         ErrorKindTitleTooLong,
+	"CONTENT_REQUIRED":       ErrorKindContentRequired,
+	"CONTENT_TOO_BIG":        ErrorKindContentTooBig,
+	"CONTENT_FORMAT_INVALID": ErrorKindContentFormatInvalid,
+	"AUTHOR_NAME_TOO_LONG":   ErrorKindAuthorNameTooLong,
+	"AUTHOR_URL_TOO_LONG":    ErrorKindAuthorURLTooLong,
+	"PAGE_NOT_FOUND":         ErrorKindPageNotFound,
+	"PAGE_ACCESS_DENIED":     ErrorKindPageAccessDenied,
+	"PAGE_SAVE_FAILED":       ErrorKindPageSaveFailed,
+}
+
+// ParseErrorKind maps a Telegraph error description to an ErrorKind
+func ParseErrorKind(description string) ErrorKind {
+	if kind, ok := errorKinds[description]; ok {
+		return kind
+	}
+	if strings.HasPrefix(description, "FLOOD_WAIT_") {
+		return ErrorKindFloodWait
+	}
+	return ErrorKindUnknown
+}
+
+// String returns the Telegraph description for the kind
+func (k ErrorKind) String() string {
+	if k == ErrorKindFloodWait {
+		return "FLOOD_WAIT"
+	}
+	for description, kind := range errorKinds {
+		if kind == k {
+			return description
+		}
+	}
+	return "UNKNOWN"
+}
+
+// FloodWait returns the wait asked for by a FLOOD_WAIT_X error, X seconds, and
+// whether the error is such an error
+func (e *APIError) FloodWait() (time.Duration, bool) {
+	seconds, ok := strings.CutPrefix(e.Description, "FLOOD_WAIT_")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(seconds)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return time.Duration(n) * time.Second, true
 }
 
 func (e *APIError) Error() string {
@@ -38,6 +128,36 @@ type Account struct {
 	PageCount int    `json:"page_count,omitempty"`
 }
 
+// authTokenPattern matches the token segment of an auth URL
+var authTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ParseAuthURL validates an auth URL, as returned in Account.AuthURL, and returns its token
+//
+// Auth URLs have the form https://edit.telegra.ph/auth/<token>. Opening one in a
+// browser logs the user in to edit the account's pages; it is valid for
+// 5 minutes and can be used only once.
+func ParseAuthURL(authURL string) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid auth URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host != "edit.telegra.ph" {
+		return "", fmt.Errorf("invalid auth URL: must start with https://edit.telegra.ph/auth/")
+	}
+	token, ok := strings.CutPrefix(u.Path, "/auth/")
+	if !ok || !authTokenPattern.MatchString(token) {
+		return "", fmt.Errorf("invalid auth URL: path must be /auth/<token>")
+	}
+	return token, nil
+}
+
+// AuthToken returns the token of the account's auth URL, or "" if AuthURL is
+// empty or malformed. Use ParseAuthURL to find out why an auth URL is rejected.
+func (a Account) AuthToken() string {
+	token, _ := ParseAuthURL(a.AuthURL)
+	return token
+}
+
 // Page represents a Telegraph page
 type Page struct {
 	Path        string `json:"path"`
@@ -52,6 +172,29 @@ type Page struct {
 	CanEdit     bool   `json:"can_edit,omitempty"`
 }
 
+// ContentJSON returns the content array exactly as it is serialized in API requests
+func (p Page) ContentJSON() ([]byte, error) {
+	data, err := json.Marshal(p.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal content: %w", err)
+	}
+	return data, nil
+}
+
+// ErrMalformedPage is returned by Page.Validate for pages missing required fields
+var ErrMalformedPage = errors.New("malformed page")
+
+// Validate checks that a page returned by createPage or editPage has a path and URL
+func (p Page) Validate() error {
+	if p.Path == "" {
+		return fmt.Errorf("%w: path is missing", ErrMalformedPage)
+	}
+	if p.URL == "" {
+		return fmt.Errorf("%w: url is missing", ErrMalformedPage)
+	}
+	return nil
+}
+
 // PageList represents a list of Telegraph pages
 type PageList struct {
 	TotalCount int    `json:"total_count"`
@@ -64,6 +207,17 @@ type PageViews struct {
 }
 
 // Node represents a DOM node in Telegraph content
+//
+// Nodes marshal to Telegraph's content format: a text node (one without a Tag)
+// is a bare JSON string, and an element is an object with tag, attrs and
+// children, where the Content of an element is written as its first child.
+// Nodes also unmarshal from either form.
+//
+// Nodes marshal deterministically: encoding/json writes the keys of Attrs in
+// sorted order, so the same content always has the same JSON, and checksums
+// and diffs of stored content are stable. The API may order attributes
+// differently, so compare content from the API with NodesEqual or
+// ContentChecksum rather than byte for byte.
 type Node struct {
 	// Tag is the HTML tag name (e.g., "p", "strong", "em", "a", "br", "code", "pre", etc.)
 	Tag string `json:"tag,omitempty"`
@@ -77,6 +231,42 @@ type Node struct {
 	Content string `json:",omitempty"`
 }
 
+// wireNode is the JSON object form of an element in Telegraph's content format
+type wireNode struct {
+	Tag      string            `json:"tag"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+	Children []interface{}     `json:"children,omitempty"`
+}
+
+// MarshalJSON encodes the node in Telegraph's content format
+func (n Node) MarshalJSON() ([]byte, error) {
+	if n.Tag == "" {
+		return json.Marshal(n.Content)
+	}
+
+	children := n.Children
+	if n.Content != "" {
+		children = append([]interface{}{n.Content}, n.Children...)
+	}
+	return json.Marshal(wireNode{Tag: n.Tag, Attrs: n.Attrs, Children: children})
+}
+
+// UnmarshalJSON decodes a node from a JSON string or element object
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*n = Node{Content: text}
+		return nil
+	}
+
+	var element wireNode
+	if err := json.Unmarshal(data, &element); err != nil {
+		return err
+	}
+	*n = Node{Tag: element.Tag, Attrs: element.Attrs, Children: element.Children}
+	return nil
+}
+
 // CreateAccountRequest represents the request for creating a Telegraph account
 type CreateAccountRequest struct {
 	// ShortName is the account name (1-32 characters)
@@ -89,22 +279,31 @@ type CreateAccountRequest struct {
 
 // Validate validates the CreateAccountRequest
 func (r *CreateAccountRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the CreateAccountRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *CreateAccountRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the CreateAccountRequest in order
+func (r *CreateAccountRequest) validationErrors() []error {
+	var errs []error
 	if r.ShortName == "" {
-		return fmt.Errorf("short_name is required")
+		errs = append(errs, fmt.Errorf("short_name is required"))
 	}
 	if len(r.ShortName) > 32 {
-		return fmt.Errorf("short_name must be at most 32 characters")
+		errs = append(errs, fmt.Errorf("short_name must be at most 32 characters"))
 	}
 	if len(r.AuthorName) > 128 {
-		return fmt.Errorf("author_name must be at most 128 characters")
-	}
-	if len(r.AuthorURL) > 512 {
-		return fmt.Errorf("author_url must be at most 512 characters")
+		errs = append(errs, fmt.Errorf("author_name must be at most 128 characters"))
 	}
-	if r.AuthorURL != "" && !isValidURL(r.AuthorURL) {
-		return fmt.Errorf("author_url must be a valid URL")
+	if err := validateAuthorURL(&r.AuthorURL); err != nil {
+		errs = append(errs, err)
 	}
-	return nil
+	return errs
 }
 
 // EditAccountInfoRequest represents the request for editing account information
@@ -121,22 +320,31 @@ type EditAccountInfoRequest struct {
 
 // Validate validates the EditAccountInfoRequest
 func (r *EditAccountInfoRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the EditAccountInfoRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *EditAccountInfoRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the EditAccountInfoRequest in order
+func (r *EditAccountInfoRequest) validationErrors() []error {
+	var errs []error
 	if r.AccessToken == "" {
-		return fmt.Errorf("access_token is required")
+		errs = append(errs, fmt.Errorf("access_token is required"))
 	}
 	if r.ShortName != "" && len(r.ShortName) > 32 {
-		return fmt.Errorf("short_name must be at most 32 characters")
+		errs = append(errs, fmt.Errorf("short_name must be at most 32 characters"))
 	}
 	if len(r.AuthorName) > 128 {
-		return fmt.Errorf("author_name must be at most 128 characters")
+		errs = append(errs, fmt.Errorf("author_name must be at most 128 characters"))
 	}
-	if len(r.AuthorURL) > 512 {
-		return fmt.Errorf("author_url must be at most 512 characters")
-	}
-	if r.AuthorURL != "" && !isValidURL(r.AuthorURL) {
-		return fmt.Errorf("author_url must be a valid URL")
+	if err := validateAuthorURL(&r.AuthorURL); err != nil {
+		errs = append(errs, err)
 	}
-	return nil
+	return errs
 }
 
 // GetAccountInfoRequest represents the request for getting account information
@@ -150,8 +358,20 @@ type GetAccountInfoRequest struct {
 
 // Validate validates the GetAccountInfoRequest
 func (r *GetAccountInfoRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the GetAccountInfoRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *GetAccountInfoRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the GetAccountInfoRequest in order
+func (r *GetAccountInfoRequest) validationErrors() []error {
+	var errs []error
 	if r.AccessToken == "" {
-		return fmt.Errorf("access_token is required")
+		errs = append(errs, fmt.Errorf("access_token is required"))
 	}
 
 	validFields := map[string]bool{
@@ -164,11 +384,11 @@ func (r *GetAccountInfoRequest) Validate() error {
 
 	for _, field := range r.Fields {
 		if !validFields[field] {
-			return fmt.Errorf("invalid field: %s", field)
+			errs = append(errs, fmt.Errorf("invalid field: %s", field))
 		}
 	}
 
-	return nil
+	return errs
 }
 
 // CreatePageRequest represents the request for creating a Telegraph page
@@ -185,32 +405,72 @@ type CreatePageRequest struct {
 	Content []Node `json:"content"`
 	// ReturnContent determines whether to return the content in the response
 	ReturnContent bool `json:"return_content,omitempty"`
+	// RawTitle keeps leading and trailing whitespace in Title, which Validate trims otherwise
+	RawTitle bool `json:"-"`
 }
 
 // Validate validates the CreatePageRequest
 func (r *CreatePageRequest) Validate() error {
-	if r.AccessToken == "" {
-		return fmt.Errorf("access_token is required")
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the CreatePageRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *CreatePageRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the CreatePageRequest in order
+func (r *CreatePageRequest) validationErrors() []error {
+	return append(r.tokenErrors(), r.contentFieldErrors()...)
+}
+
+// validateFields validates every field of the CreatePageRequest except Content
+func (r *CreatePageRequest) validateFields() error {
+	return firstError(r.fieldErrors())
+}
+
+// validateContentFields validates every field of the CreatePageRequest except
+// AccessToken, for requests whose token is not known yet
+func (r *CreatePageRequest) validateContentFields() error {
+	return firstError(r.contentFieldErrors())
+}
+
+// fieldErrors returns the failed checks of every field of the CreatePageRequest except Content
+func (r *CreatePageRequest) fieldErrors() []error {
+	return append(r.tokenErrors(), r.pageFieldErrors()...)
+}
+
+// contentFieldErrors returns the failed checks of every field of the CreatePageRequest except AccessToken
+func (r *CreatePageRequest) contentFieldErrors() []error {
+	errs := r.pageFieldErrors()
+	if len(r.Content) == 0 {
+		errs = append(errs, fmt.Errorf("content is required"))
 	}
-	if r.Title == "" {
-		return fmt.Errorf("title is required")
+	return errs
+}
+
+// tokenErrors returns the failed checks of the AccessToken of the CreatePageRequest
+func (r *CreatePageRequest) tokenErrors() []error {
+	if r.AccessToken == "" {
+		return []error{fmt.Errorf("access_token is required")}
 	}
-	if len(r.Title) > 256 {
-		return fmt.Errorf("title must be at most 256 characters")
+	return nil
+}
+
+// pageFieldErrors returns the failed checks of the title and author fields of the CreatePageRequest
+func (r *CreatePageRequest) pageFieldErrors() []error {
+	var errs []error
+	if err := validateTitle(&r.Title, r.RawTitle); err != nil {
+		errs = append(errs, err)
 	}
 	if len(r.AuthorName) > 128 {
-		return fmt.Errorf("author_name must be at most 128 characters")
-	}
-	if len(r.AuthorURL) > 512 {
-		return fmt.Errorf("author_url must be at most 512 characters")
+		errs = append(errs, fmt.Errorf("author_name must be at most 128 characters"))
 	}
-	if r.AuthorURL != "" && !isValidURL(r.AuthorURL) {
-		return fmt.Errorf("author_url must be a valid URL")
-	}
-	if len(r.Content) == 0 {
-		return fmt.Errorf("content is required")
+	if err := validateAuthorURL(&r.AuthorURL); err != nil {
+		errs = append(errs, err)
 	}
-	return nil
+	return errs
 }
 
 // EditPageRequest represents the request for editing a Telegraph page
@@ -229,33 +489,89 @@ type EditPageRequest struct {
 	Content []Node `json:"content"`
 	// ReturnContent determines whether to return the content in the response
 	ReturnContent bool `json:"return_content,omitempty"`
+	// RawTitle keeps leading and trailing whitespace in Title, which Validate trims otherwise
+	RawTitle bool `json:"-"`
 }
 
 // Validate validates the EditPageRequest
 func (r *EditPageRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the EditPageRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *EditPageRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the EditPageRequest in order
+func (r *EditPageRequest) validationErrors() []error {
+	var errs []error
 	if r.AccessToken == "" {
-		return fmt.Errorf("access_token is required")
+		errs = append(errs, fmt.Errorf("access_token is required"))
 	}
 	if r.Path == "" {
-		return fmt.Errorf("path is required")
+		errs = append(errs, fmt.Errorf("path is required"))
+	} else if err := ValidatePagePath(r.Path); err != nil {
+		errs = append(errs, err)
 	}
-	if r.Title == "" {
-		return fmt.Errorf("title is required")
+	if err := validateTitle(&r.Title, r.RawTitle); err != nil {
+		errs = append(errs, err)
+	}
+	if len(r.AuthorName) > 128 {
+		errs = append(errs, fmt.Errorf("author_name must be at most 128 characters"))
+	}
+	if err := validateAuthorURL(&r.AuthorURL); err != nil {
+		errs = append(errs, err)
+	}
+	if len(r.Content) == 0 {
+		errs = append(errs, fmt.Errorf("content is required"))
 	}
-	if len(r.Title) > 256 {
+	return errs
+}
+
+// firstError returns the first error of errs, or nil if there is none
+func firstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// validateTitle normalizes a page title and checks its length
+func validateTitle(title *string, raw bool) error {
+	if err := normalizeTitle(title, raw); err != nil {
+		return err
+	}
+	if len(*title) > 256 {
 		return fmt.Errorf("title must be at most 256 characters")
 	}
-	if len(r.AuthorName) > 128 {
-		return fmt.Errorf("author_name must be at most 128 characters")
+	return nil
+}
+
+// validateAuthorURL normalizes an author URL and checks its length
+func validateAuthorURL(authorURL *string) error {
+	if err := normalizeAuthorURL(authorURL); err != nil {
+		return err
 	}
-	if len(r.AuthorURL) > 512 {
+	if len(*authorURL) > 512 {
 		return fmt.Errorf("author_url must be at most 512 characters")
 	}
-	if r.AuthorURL != "" && !isValidURL(r.AuthorURL) {
-		return fmt.Errorf("author_url must be a valid URL")
+	return nil
+}
+
+// normalizeTitle trims surrounding whitespace from a page title unless raw is
+// set, and rejects empty and whitespace-only titles
+func normalizeTitle(title *string, raw bool) error {
+	if *title == "" {
+		return fmt.Errorf("title is required")
 	}
-	if len(r.Content) == 0 {
-		return fmt.Errorf("content is required")
+	trimmed := strings.TrimSpace(*title)
+	if trimmed == "" {
+		return fmt.Errorf("title must not be only whitespace")
+	}
+	if !raw {
+		*title = trimmed
 	}
 	return nil
 }
@@ -270,9 +586,51 @@ type GetPageRequest struct {
 
 // Validate validates the GetPageRequest
 func (r *GetPageRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the GetPageRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *GetPageRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the GetPageRequest in order
+func (r *GetPageRequest) validationErrors() []error {
+	var errs []error
 	if r.Path == "" {
+		errs = append(errs, fmt.Errorf("path is required"))
+	} else if err := ValidatePagePath(r.Path); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ValidatePagePath checks that path is well-formed for a Telegraph page, such
+// as "My-Article-12-15"
+//
+// A path is a single segment made of letters, digits, dashes, underscores and
+// dots, without slashes or whitespace. It catches page URLs, paths with a
+// leading slash and other user input that cannot name a page, before a call is
+// made; it cannot tell whether the page exists.
+func ValidatePagePath(path string) error {
+	if path == "" {
 		return fmt.Errorf("path is required")
 	}
+	if strings.Contains(path, "/") {
+		return fmt.Errorf("path must be a single segment without slashes, got %q", path)
+	}
+	if path == "." || path == ".." {
+		return fmt.Errorf("path must not be %q", path)
+	}
+	for _, r := range path {
+		switch {
+		case unicode.IsSpace(r):
+			return fmt.Errorf("path must not contain whitespace, got %q", path)
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.':
+			return fmt.Errorf("path contains invalid character %q", r)
+		}
+	}
 	return nil
 }
 
@@ -282,22 +640,35 @@ type GetPageListRequest struct {
 	AccessToken string `json:"access_token"`
 	// Offset is the sequential number of the first page to be returned (default: 0)
 	Offset int `json:"offset,omitempty"`
-	// Limit is the number of pages to be returned (0-200, default: 50)
+	// Limit is the number of pages to be returned (0-200, default: 50, or the
+	// client's WithDefaultPageListLimit)
 	Limit int `json:"limit,omitempty"`
 }
 
 // Validate validates the GetPageListRequest
 func (r *GetPageListRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the GetPageListRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *GetPageListRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the GetPageListRequest in order
+func (r *GetPageListRequest) validationErrors() []error {
+	var errs []error
 	if r.AccessToken == "" {
-		return fmt.Errorf("access_token is required")
+		errs = append(errs, fmt.Errorf("access_token is required"))
 	}
 	if r.Offset < 0 {
-		return fmt.Errorf("offset must be non-negative")
+		errs = append(errs, fmt.Errorf("offset must be non-negative"))
 	}
 	if r.Limit < 0 || r.Limit > 200 {
-		return fmt.Errorf("limit must be between 0 and 200")
+		errs = append(errs, fmt.Errorf("limit must be between 0 and 200"))
 	}
-	return nil
+	return errs
 }
 
 // GetViewsRequest represents the request for getting page views
@@ -316,21 +687,84 @@ type GetViewsRequest struct {
 
 // Validate validates the GetViewsRequest
 func (r *GetViewsRequest) Validate() error {
+	return firstError(r.validationErrors())
+}
+
+// ValidateAll validates the GetViewsRequest like Validate but reports every
+// failed check, joined with errors.Join, instead of only the first
+func (r *GetViewsRequest) ValidateAll() error {
+	return errors.Join(r.validationErrors()...)
+}
+
+// validationErrors returns the failed checks of the GetViewsRequest in order
+func (r *GetViewsRequest) validationErrors() []error {
+	var errs []error
 	if r.Path == "" {
-		return fmt.Errorf("path is required")
+		errs = append(errs, fmt.Errorf("path is required"))
 	}
 	if r.Year != 0 && (r.Year < 2000 || r.Year > 2100) {
-		return fmt.Errorf("year must be between 2000 and 2100")
+		errs = append(errs, fmt.Errorf("year must be between 2000 and 2100"))
 	}
 	if r.Month != 0 && (r.Month < 1 || r.Month > 12) {
-		return fmt.Errorf("month must be between 1 and 12")
+		errs = append(errs, fmt.Errorf("month must be between 1 and 12"))
 	}
 	if r.Day != 0 && (r.Day < 1 || r.Day > 31) {
-		return fmt.Errorf("day must be between 1 and 31")
+		errs = append(errs, fmt.Errorf("day must be between 1 and 31"))
 	}
 	if r.Hour != 0 && (r.Hour < 0 || r.Hour > 24) {
-		return fmt.Errorf("hour must be between 0 and 24")
+		errs = append(errs, fmt.Errorf("hour must be between 0 and 24"))
+	}
+	return errs
+}
+
+// NormalizeAuthorURL validates an author URL and returns it in ASCII-compatible form
+//
+// Internationalized domain names are converted to punycode, and non-ASCII
+// characters elsewhere in the URL are percent-encoded, so that
+// "https://例え.jp/ページ" becomes "https://xn--r8jz45g.jp/%E3%83%9A%E3%83%BC%E3%82%B8".
+// Only http and https URLs are accepted.
+func NormalizeAuthorURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid URL: scheme must be http or https")
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL: host is required")
+	}
+
+	// IPv6 literals are already ASCII and are not domain names
+	if !strings.Contains(u.Hostname(), ":") {
+		host, err := idna.Lookup.ToASCII(u.Hostname())
+		if err != nil {
+			return "", fmt.Errorf("invalid URL host: %w", err)
+		}
+		if port := u.Port(); port != "" {
+			host += ":" + port
+		}
+		u.Host = host
+	}
+
+	normalized := u.String()
+	if !isValidURL(normalized) {
+		return "", fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	return normalized, nil
+}
+
+// normalizeAuthorURL replaces a non-empty author URL with its normalized form
+func normalizeAuthorURL(authorURL *string) error {
+	if *authorURL == "" {
+		return nil
+	}
+	normalized, err := NormalizeAuthorURL(*authorURL)
+	if err != nil {
+		return fmt.Errorf("author_url must be a valid URL")
 	}
+	*authorURL = normalized
 	return nil
 }
 
@@ -357,6 +791,41 @@ func NewContentBuilder() *ContentBuilder {
 	}
 }
 
+// NewContentBuilderFromNodes creates a content builder that starts with a copy of
+// nodes, such as the content returned by GetPage, so it can be extended and re-submitted.
+// Children may be strings, Node values or pointers, or JSON-decoded objects; the
+// latter two are converted to Node values.
+//
+// Example:
+//
+//	page, _ := client.GetPage(ctx, &telegraph.GetPageRequest{Path: path, ReturnContent: true})
+//	content := telegraph.NewContentBuilderFromNodes(page.Content).AddParagraph("Updated").Build()
+func NewContentBuilderFromNodes(nodes []Node) *ContentBuilder {
+	cb := NewContentBuilder()
+	for _, node := range nodes {
+		cb.nodes = append(cb.nodes, normalizeNode(node))
+	}
+	return cb
+}
+
+// normalizeNode deep copies node, converting its children to strings and Node values
+func normalizeNode(node Node) Node {
+	result := cloneNode(Node{Tag: node.Tag, Attrs: node.Attrs, Content: node.Content})
+	if node.Children != nil {
+		result.Children = make([]interface{}, 0, len(node.Children))
+		for _, child := range node.Children {
+			if text, ok := child.(string); ok {
+				result.Children = append(result.Children, text)
+				continue
+			}
+			if ch, ok := asNode(child); ok {
+				result.Children = append(result.Children, normalizeNode(ch))
+			}
+		}
+	}
+	return result
+}
+
 // AddParagraph adds a paragraph to the content
 func (cb *ContentBuilder) AddParagraph(text string) *ContentBuilder {
 	cb.nodes = append(cb.nodes, Node{
@@ -368,6 +837,28 @@ func (cb *ContentBuilder) AddParagraph(text string) *ContentBuilder {
 	return cb
 }
 
+// AddParagraphs adds a paragraph for every non-empty text, in order
+//
+// Example:
+//
+//	cb.AddParagraphs(strings.Split(text, "\n\n")...)
+func (cb *ContentBuilder) AddParagraphs(texts ...string) *ContentBuilder {
+	for _, text := range texts {
+		if text != "" {
+			cb.AddParagraph(text)
+		}
+	}
+	return cb
+}
+
+// AddParagraphsKeepEmpty is like AddParagraphs but adds an empty paragraph for every empty text
+func (cb *ContentBuilder) AddParagraphsKeepEmpty(texts ...string) *ContentBuilder {
+	for _, text := range texts {
+		cb.AddParagraph(text)
+	}
+	return cb
+}
+
 // AddHeading adds a heading to the content (h3 or h4)
 func (cb *ContentBuilder) AddHeading(text string, level int) *ContentBuilder {
 	tag := "h3"
@@ -403,6 +894,37 @@ func (cb *ContentBuilder) AddLink(text, url string) *ContentBuilder {
 	return cb
 }
 
+// LinkItem is a link of a link list
+type LinkItem struct {
+	// Text is the link text; the URL is shown if it is empty
+	Text string
+	// URL is the link target
+	URL string
+}
+
+// AddLinkList adds a bulleted list with one link per item
+func (cb *ContentBuilder) AddLinkList(links []LinkItem) *ContentBuilder {
+	items := make([]interface{}, 0, len(links))
+	for _, link := range links {
+		text := link.Text
+		if text == "" {
+			text = link.URL
+		}
+		items = append(items, Node{
+			Tag: "li",
+			Children: []interface{}{
+				Node{
+					Tag:      "a",
+					Attrs:    map[string]string{"href": link.URL},
+					Children: []interface{}{Node{Content: text}},
+				},
+			},
+		})
+	}
+	cb.nodes = append(cb.nodes, Node{Tag: "ul", Children: items})
+	return cb
+}
+
 // AddImage adds an image to the content
 func (cb *ContentBuilder) AddImage(src string) *ContentBuilder {
 	cb.nodes = append(cb.nodes, Node{
@@ -425,6 +947,69 @@ func (cb *ContentBuilder) AddBlockquote(text string) *ContentBuilder {
 	return cb
 }
 
+// AddQuoteWithCite adds a blockquote attributed to author
+//
+// Telegraph has no footer or cite tags, so the attribution is placed on its
+// own line after the quote as emphasized text, e.g. "— Author". An empty
+// author adds a plain blockquote.
+func (cb *ContentBuilder) AddQuoteWithCite(quote, author string) *ContentBuilder {
+	if author == "" {
+		return cb.AddBlockquote(quote)
+	}
+	cb.nodes = append(cb.nodes, Node{
+		Tag: "blockquote",
+		Children: []interface{}{
+			Node{Content: quote},
+			Node{Tag: "br"},
+			Node{
+				Tag:      "em",
+				Children: []interface{}{Node{Content: "— " + author}},
+			},
+		},
+	})
+	return cb
+}
+
+// AddSectionByline adds a byline crediting author with the section that follows
+//
+// Telegraph pages have a single author, so per-section bylines are part of the
+// content: a paragraph of emphasized text, e.g. "By Author". An empty author
+// adds nothing.
+//
+// Example:
+//
+//	cb.AddHeading("Part two", 3).AddSectionByline("Jane Doe").AddParagraph(text)
+func (cb *ContentBuilder) AddSectionByline(author string) *ContentBuilder {
+	if author == "" {
+		return cb
+	}
+	cb.nodes = append(cb.nodes, Node{
+		Tag: "p",
+		Children: []interface{}{
+			Node{
+				Tag:      "em",
+				Children: []interface{}{Node{Content: "By " + author}},
+			},
+		},
+	})
+	return cb
+}
+
+// AddInlineCode adds a paragraph holding text as inline code, such as a
+// command or key combination, unlike AddCodeBlock, which adds a pre block
+func (cb *ContentBuilder) AddInlineCode(text string) *ContentBuilder {
+	cb.nodes = append(cb.nodes, Node{
+		Tag: "p",
+		Children: []interface{}{
+			Node{
+				Tag:      "code",
+				Children: []interface{}{Node{Content: text}},
+			},
+		},
+	})
+	return cb
+}
+
 // AddCodeBlock adds a code block to the content
 func (cb *ContentBuilder) AddCodeBlock(code string) *ContentBuilder {
 	cb.nodes = append(cb.nodes, Node{
@@ -449,6 +1034,31 @@ func (cb *ContentBuilder) Build() []Node {
 	return cb.nodes
 }
 
+// MaxContentSize is the maximum size of serialized page content accepted by Telegraph (64KB)
+const MaxContentSize = 64 * 1024
+
+// ContentSizeWarningRatio is the fraction of MaxContentSize above which content is near the limit
+const ContentSizeWarningRatio = 0.9
+
+// ContentByteSize returns the size in bytes of content as serialized in API requests
+func ContentByteSize(nodes []Node) int {
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// ByteSize returns the size in bytes of the built content as sent by CreatePage
+func (cb *ContentBuilder) ByteSize() int {
+	return ContentByteSize(cb.nodes)
+}
+
+// NearLimit reports whether the content size has reached ContentSizeWarningRatio of MaxContentSize
+func (cb *ContentBuilder) NearLimit() bool {
+	return float64(cb.ByteSize()) >= ContentSizeWarningRatio*MaxContentSize
+}
+
 // String returns a string representation of the content
 func (cb *ContentBuilder) String() string {
 	var result strings.Builder
@@ -458,22 +1068,597 @@ func (cb *ContentBuilder) String() string {
 	return result.String()
 }
 
-// nodeToString converts a Node to its string representation
-func nodeToString(node interface{}) string {
+// dumpTextLimit is the number of characters of text shown per node by DumpContent
+const dumpTextLimit = 40
+
+// DumpContent returns an indented, human-readable outline of a content tree for
+// debugging. Each element is shown with its tag and sorted attributes, and text
+// is quoted and truncated. The output is not HTML.
+//
+// Example output:
+//
+//	p
+//	  "Hello, "
+//	  a href="https://example.com"
+//	    "world"
+func DumpContent(nodes []Node) string {
 	var result strings.Builder
+	FdumpContent(&result, nodes)
+	return result.String()
+}
+
+// FdumpContent writes the outline produced by DumpContent to w
+func FdumpContent(w io.Writer, nodes []Node) error {
+	type entry struct {
+		value interface{}
+		depth int
+	}
+
+	stack := make([]entry, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		stack = append(stack, entry{nodes[i], 0})
+	}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var line string
+		var children []interface{}
+		if text, ok := top.value.(string); ok {
+			line = quoteTruncated(text)
+		} else if node, ok := asNode(top.value); ok {
+			line = dumpNodeLine(node)
+			children = node.Children
+		} else {
+			line = fmt.Sprintf("<%T>", top.value)
+		}
 
-	// node can be of type Node or string
-	switch n := node.(type) {
+		if _, err := fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", top.depth), line); err != nil {
+			return err
+		}
+
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, entry{children[i], top.depth + 1})
+		}
+	}
+	return nil
+}
+
+// dumpNodeLine formats a single node for DumpContent
+func dumpNodeLine(node Node) string {
+	if node.Tag == "" {
+		return quoteTruncated(node.Content)
+	}
+
+	parts := []string{node.Tag}
+	keys := make([]string, 0, len(node.Attrs))
+	for k := range node.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, strconv.Quote(node.Attrs[k])))
+	}
+	if node.Content != "" {
+		parts = append(parts, quoteTruncated(node.Content))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteTruncated quotes text, shortening it to dumpTextLimit characters
+func quoteTruncated(text string) string {
+	runes := []rune(text)
+	if len(runes) > dumpTextLimit {
+		return strconv.Quote(string(runes[:dumpTextLimit])) + "..."
+	}
+	return strconv.Quote(text)
+}
+
+// SplitContent splits top-level content at index at, returning deep copies of
+// nodes[:at] and nodes[at:]. The index is clamped to the bounds of nodes.
+//
+// Example:
+//
+//	head, tail := telegraph.SplitContent(content, 10)
+func SplitContent(nodes []Node, at int) (head, tail []Node) {
+	if at < 0 {
+		at = 0
+	}
+	if at > len(nodes) {
+		at = len(nodes)
+	}
+	return CloneContent(nodes[:at]), CloneContent(nodes[at:])
+}
+
+// SplitByHeading splits top-level content into parts, each starting at a heading
+//
+// Level 4 starts a part at every h3 and h4, and any other level at every h3
+// only, so that h4 sections stay within their h3 chapter. Content before the
+// first heading is a part of its own, and is omitted if empty. The parts are
+// deep copies of content.
+//
+// Example:
+//
+//	chapters := telegraph.SplitByHeading(book, 3)
+func SplitByHeading(nodes []Node, level int) [][]Node {
+	splits := map[string]bool{"h3": true}
+	if level == 4 {
+		splits["h4"] = true
+	}
+
+	var parts [][]Node
+	start := 0
+	for i, node := range nodes {
+		if i > start && splits[strings.ToLower(node.Tag)] {
+			parts = append(parts, CloneContent(nodes[start:i]))
+			start = i
+		}
+	}
+	if start < len(nodes) {
+		parts = append(parts, CloneContent(nodes[start:]))
+	}
+	return parts
+}
+
+// CloneContent returns a deep copy of content, sharing no attributes or children with it
+func CloneContent(nodes []Node) []Node {
+	result := make([]Node, len(nodes))
+	for i, node := range nodes {
+		result[i] = cloneNode(node)
+	}
+	return result
+}
+
+func cloneNode(node Node) Node {
+	clone := Node{
+		Tag:     node.Tag,
+		Content: node.Content,
+	}
+	if node.Attrs != nil {
+		clone.Attrs = make(map[string]string, len(node.Attrs))
+		for k, v := range node.Attrs {
+			clone.Attrs[k] = v
+		}
+	}
+	if node.Children != nil {
+		clone.Children = make([]interface{}, len(node.Children))
+		for i, child := range node.Children {
+			clone.Children[i] = cloneChild(child)
+		}
+	}
+	return clone
+}
+
+// cloneChild deep copies a child value, which is a Node, a string, or a JSON-decoded value
+func cloneChild(child interface{}) interface{} {
+	switch ch := child.(type) {
 	case Node:
-		if n.Content != "" {
-			result.WriteString(n.Content)
+		return cloneNode(ch)
+	case *Node:
+		if ch == nil {
+			return ch
+		}
+		clone := cloneNode(*ch)
+		return &clone
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(ch))
+		for k, v := range ch {
+			clone[k] = cloneChild(v)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(ch))
+		for i, v := range ch {
+			clone[i] = cloneChild(v)
+		}
+		return clone
+	default:
+		return child
+	}
+}
+
+// CoalesceInline returns a copy of content in which adjacent inline nodes with
+// the same tag and attributes, such as two strong runs, are merged into one, and
+// adjacent text is joined. Nodes are only merged when they are direct siblings,
+// so runs in different blocks or separated by text are kept apart.
+//
+// Example:
+//
+//	// <b>a</b><b>b</b> becomes <strong>ab</strong>
+//	content = telegraph.CoalesceInline(content)
+func CoalesceInline(nodes []Node) []Node {
+	result := make([]Node, len(nodes))
+	for i, node := range nodes {
+		result[i] = normalizeNode(node)
+		result[i].Children = coalesceChildren(result[i].Children)
+	}
+	return result
+}
+
+// coalesceChildren merges adjacent mergeable values of normalized children in place
+func coalesceChildren(children []interface{}) []interface{} {
+	if children == nil {
+		return nil
+	}
+
+	result := children[:0]
+	for _, child := range children {
+		if node, ok := child.(Node); ok {
+			node.Children = coalesceChildren(node.Children)
+			child = node
+		}
+
+		if len(result) > 0 {
+			switch prev := result[len(result)-1].(type) {
+			case string:
+				if text, ok := child.(string); ok {
+					result[len(result)-1] = prev + text
+					continue
+				}
+			case Node:
+				if node, ok := child.(Node); ok && canCoalesce(prev, node) {
+					prev.Children = coalesceChildren(append(prev.Children, node.Children...))
+					result[len(result)-1] = prev
+					continue
+				}
+			}
+		}
+		result = append(result, child)
+	}
+	return result
+}
+
+// canCoalesce reports whether two adjacent nodes are the same inline element
+func canCoalesce(a, b Node) bool {
+	if !inlineTags[a.Tag] || a.Tag != b.Tag || a.Content != "" || b.Content != "" || len(a.Attrs) != len(b.Attrs) {
+		return false
+	}
+	for k, v := range a.Attrs {
+		if bv, ok := b.Attrs[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// StripDataURIImages returns a copy of content without img nodes whose src is a
+// data: URI
+//
+// Images inlined as data URIs count in full towards the 64KB content limit and
+// are not displayed by Telegraph, so stripping them is often enough to make
+// converted content fit. Figures left empty are removed as well. Use
+// ReplaceDataURIImages to keep a placeholder instead.
+//
+// Example:
+//
+//	content = telegraph.StripDataURIImages(content)
+func StripDataURIImages(nodes []Node) []Node {
+	return replaceDataURIImages(nodes, nil)
+}
+
+// ReplaceDataURIImages returns a copy of content in which img nodes whose src is
+// a data: URI are replaced with a copy of placeholder
+//
+// Example:
+//
+//	content = telegraph.ReplaceDataURIImages(content, telegraph.Node{
+//		Tag:      "em",
+//		Children: []interface{}{"[image removed]"},
+//	})
+func ReplaceDataURIImages(nodes []Node, placeholder Node) []Node {
+	return replaceDataURIImages(nodes, &placeholder)
+}
+
+// replaceDataURIImages replaces data URI images with placeholder, or removes
+// them and the figures they leave empty if placeholder is nil
+func replaceDataURIImages(nodes []Node, placeholder *Node) []Node {
+	var replace func(children []interface{}) []interface{}
+	replace = func(children []interface{}) []interface{} {
+		result := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			node, ok := child.(Node)
+			if !ok {
+				result = append(result, child)
+				continue
+			}
+			if isDataURIImage(node) {
+				if placeholder != nil {
+					result = append(result, cloneNode(*placeholder))
+				}
+				continue
+			}
+			if node.Children != nil {
+				node.Children = replace(node.Children)
+				if len(node.Children) == 0 && strings.EqualFold(node.Tag, "figure") {
+					continue
+				}
+			}
+			result = append(result, node)
+		}
+		return result
+	}
+
+	children := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		children[i] = normalizeNode(node)
+	}
+
+	result := make([]Node, 0, len(nodes))
+	for _, child := range replace(children) {
+		result = append(result, child.(Node))
+	}
+	return result
+}
+
+// isDataURIImage reports whether node is an img whose src is a data: URI
+func isDataURIImage(node Node) bool {
+	src := strings.TrimSpace(node.Attrs["src"])
+	return strings.EqualFold(node.Tag, "img") && len(src) >= 5 && strings.EqualFold(src[:5], "data:")
+}
+
+// NodesEqual reports whether two slices of content are equivalent
+//
+// Content built locally and content decoded from the API represent the same
+// markup differently, so text may be a string or a text Node, elements may be
+// Nodes, *Nodes or decoded JSON objects, and adjacent text may be split or
+// joined. NodesEqual ignores those differences, as well as the case of tags
+// and nil versus empty attribute maps.
+func NodesEqual(a, b []Node) bool {
+	return nodesEqual(a, b, false)
+}
+
+// nodesEqual compares content, collapsing runs of whitespace in text to a
+// single space and ignoring whitespace-only text if normalizeWhitespace is set
+func nodesEqual(a, b []Node, normalizeWhitespace bool) bool {
+	ac := make([]interface{}, len(a))
+	for i, node := range a {
+		ac[i] = node
+	}
+	bc := make([]interface{}, len(b))
+	for i, node := range b {
+		bc[i] = node
+	}
+	return childrenEqual(ac, bc, normalizeWhitespace)
+}
+
+// childrenEqual compares two lists of children after merging adjacent text
+func childrenEqual(a, b []interface{}, normalizeWhitespace bool) bool {
+	ai := contentItems("", a, normalizeWhitespace)
+	bi := contentItems("", b, normalizeWhitespace)
+	if len(ai) != len(bi) {
+		return false
+	}
+	for i := range ai {
+		at, aText := ai[i].(string)
+		bt, bText := bi[i].(string)
+		if aText || bText {
+			if at != bt || aText != bText {
+				return false
+			}
+			continue
+		}
+		an, bn := ai[i].(Node), bi[i].(Node)
+		if !strings.EqualFold(an.Tag, bn.Tag) || len(an.Attrs) != len(bn.Attrs) {
+			return false
+		}
+		for k, v := range an.Attrs {
+			if bv, ok := bn.Attrs[k]; !ok || bv != v {
+				return false
+			}
+		}
+		if !childrenEqual(append([]interface{}{an.Content}, an.Children...), append([]interface{}{bn.Content}, bn.Children...), normalizeWhitespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// contentItems flattens content and children into a list of elements and
+// text strings, in which text nodes are replaced by their text and adjacent
+// text is merged. Empty text is dropped.
+func contentItems(content string, children []interface{}, normalizeWhitespace bool) []interface{} {
+	var items []interface{}
+	var text strings.Builder
+	text.WriteString(content)
+	flush := func() {
+		s := text.String()
+		text.Reset()
+		if normalizeWhitespace {
+			s = strings.Join(strings.Fields(s), " ")
+		}
+		if s != "" {
+			items = append(items, s)
+		}
+	}
+
+	for _, child := range children {
+		if s, ok := child.(string); ok {
+			text.WriteString(s)
+			continue
+		}
+		node, ok := asNode(child)
+		if !ok {
+			continue
+		}
+		if node.Tag == "" {
+			text.WriteString(node.Content)
+			continue
+		}
+		flush()
+		items = append(items, node)
+	}
+	flush()
+	return items
+}
+
+// ContentChecksum returns a stable SHA-256 checksum of content, hex encoded
+//
+// Content is hashed in a canonical form, so equivalent content has the same
+// checksum regardless of how it is represented: text may be a string or a
+// text Node, adjacent text may be split or joined, elements may be Nodes or
+// decoded JSON objects, tags are lowercased, and attributes are hashed in
+// sorted key order. Content that is equal by NodesEqual has equal checksums.
+//
+// Example:
+//
+//	sum, err := telegraph.ContentChecksum(page.Content)
+func ContentChecksum(nodes []Node) (string, error) {
+	children := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		children[i] = node
+	}
+	canonical, err := canonicalContent("", children, 1)
+	if err != nil {
+		return "", err
+	}
+
+	// encoding/json writes map keys in sorted order
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode content: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalContent returns the canonical form of content and children, in
+// which text is a string and elements are maps with tag, attrs and children keys
+func canonicalContent(content string, children []interface{}, depth int) ([]interface{}, error) {
+	if depth > DefaultMaxDepth {
+		return nil, fmt.Errorf("content exceeds maximum nesting depth of %d", DefaultMaxDepth)
+	}
+
+	items := contentItems(content, children, false)
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		node, ok := item.(Node)
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		nodeChildren, err := canonicalContent(node.Content, node.Children, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		element := map[string]interface{}{
+			"tag":      strings.ToLower(node.Tag),
+			"children": nodeChildren,
+		}
+		if len(node.Attrs) > 0 {
+			element["attrs"] = node.Attrs
+		}
+		result = append(result, element)
+	}
+	return result, nil
+}
+
+// templatePlaceholder matches {{name}}-style placeholders
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// RenderTemplate returns a copy of nodes with {{name}} placeholders in text
+// content replaced by the matching values from data. Tags and attributes are
+// left untouched, and placeholders without a matching key are kept as-is.
+func RenderTemplate(nodes []Node, data map[string]string) []Node {
+	return renderTemplateNodes(nodes, data, false)
+}
+
+// RenderTemplateWithAttrs is like RenderTemplate but also substitutes
+// placeholders in attribute values (e.g. href and src).
+func RenderTemplateWithAttrs(nodes []Node, data map[string]string) []Node {
+	return renderTemplateNodes(nodes, data, true)
+}
+
+// AddTemplate renders the template nodes with data and appends them to the content
+func (cb *ContentBuilder) AddTemplate(nodes []Node, data map[string]string) *ContentBuilder {
+	cb.nodes = append(cb.nodes, RenderTemplate(nodes, data)...)
+	return cb
+}
+
+func renderTemplateNodes(nodes []Node, data map[string]string, attrs bool) []Node {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]Node, len(nodes))
+	for i, node := range nodes {
+		result[i] = renderTemplateNode(node, data, attrs)
+	}
+	return result
+}
+
+func renderTemplateNode(node Node, data map[string]string, attrs bool) Node {
+	rendered := Node{
+		Tag:     node.Tag,
+		Content: renderTemplateString(node.Content, data),
+	}
+
+	if node.Attrs != nil {
+		rendered.Attrs = make(map[string]string, len(node.Attrs))
+		for k, v := range node.Attrs {
+			if attrs {
+				v = renderTemplateString(v, data)
+			}
+			rendered.Attrs[k] = v
+		}
+	}
+
+	if node.Children != nil {
+		rendered.Children = make([]interface{}, len(node.Children))
+		for i, child := range node.Children {
+			if text, ok := child.(string); ok {
+				rendered.Children[i] = renderTemplateString(text, data)
+				continue
+			}
+			// Node pointers and JSON objects are rendered as Node values, so
+			// the result shares nothing with the template
+			if ch, ok := asNode(child); ok {
+				rendered.Children[i] = renderTemplateNode(ch, data, attrs)
+				continue
+			}
+			rendered.Children[i] = child
+		}
+	}
+
+	return rendered
+}
+
+func renderTemplateString(text string, data map[string]string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		key := templatePlaceholder.FindStringSubmatch(match)[1]
+		if value, ok := data[key]; ok {
+			return value
 		}
-		for _, child := range n.Children {
-			result.WriteString(nodeToString(child))
+		return match
+	})
+}
+
+// nodeToString converts a Node to its string representation.
+// The tree is walked iteratively so that deeply nested content cannot exhaust the stack.
+func nodeToString(node interface{}) string {
+	var result strings.Builder
+
+	stack := []interface{}{node}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// node can be of type Node or string
+		switch n := top.(type) {
+		case Node:
+			if n.Content != "" {
+				result.WriteString(n.Content)
+			}
+			// Push children in reverse so they are visited in document order
+			for i := len(n.Children) - 1; i >= 0; i-- {
+				stack = append(stack, n.Children[i])
+			}
+		case string:
+			// if it's a string, we just write it directly
+			result.WriteString(n)
 		}
-	case string:
-		// if it's a string, we just write it directly
-		result.WriteString(n)
 	}
 
 	return result.String()
@@ -1,9 +1,14 @@
 package telegraph
 
 import (
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateAccountRequestValidation(t *testing.T) {
@@ -210,6 +215,16 @@ func TestCreatePageRequestValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "content is required",
 		},
+		{
+			name: "whitespace-only title",
+			req: CreatePageRequest{
+				AccessToken: "test-token",
+				Title:       " \t\n ",
+				Content:     []Node{{Tag: "p", Children: []interface{}{"Hello"}}},
+			},
+			wantErr: true,
+			errMsg:  "title must not be only whitespace",
+		},
 	}
 
 	for _, tt := range tests {
@@ -225,6 +240,105 @@ func TestCreatePageRequestValidation(t *testing.T) {
 	}
 }
 
+func TestPageRequestTitleTrimming(t *testing.T) {
+	content := []Node{{Tag: "p", Children: []interface{}{"Hello"}}}
+
+	t.Run("create", func(t *testing.T) {
+		req := &CreatePageRequest{AccessToken: "test-token", Title: "  Test Article \n", Content: content}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "Test Article", req.Title)
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		req := &EditPageRequest{AccessToken: "test-token", Path: "Test-Article-12-15", Title: "\tTest Article ", Content: content}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "Test Article", req.Title)
+
+		req = &EditPageRequest{AccessToken: "test-token", Path: "Test-Article-12-15", Title: "   ", Content: content}
+		assert.EqualError(t, req.Validate(), "title must not be only whitespace")
+	})
+
+	t.Run("raw title", func(t *testing.T) {
+		req := &CreatePageRequest{AccessToken: "test-token", Title: "  Test Article ", Content: content, RawTitle: true}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "  Test Article ", req.Title)
+	})
+
+	t.Run("not serialized", func(t *testing.T) {
+		data, err := json.Marshal(&CreatePageRequest{Title: "Test", RawTitle: true})
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "RawTitle")
+	})
+}
+
+func TestRequestValidateAll(t *testing.T) {
+	t.Run("create page reports every error", func(t *testing.T) {
+		req := &CreatePageRequest{AccessToken: "test-token", AuthorName: strings.Repeat("a", 129)}
+
+		err := req.ValidateAll()
+		require.Error(t, err)
+		assert.Equal(t, "title is required\nauthor_name must be at most 128 characters\ncontent is required", err.Error())
+
+		// Validate still stops at the first error
+		assert.EqualError(t, req.Validate(), "title is required")
+	})
+
+	t.Run("edit page reports every error", func(t *testing.T) {
+		req := &EditPageRequest{Title: "Test"}
+
+		err := req.ValidateAll()
+		require.Error(t, err)
+		assert.Equal(t, "access_token is required\npath is required\ncontent is required", err.Error())
+	})
+
+	t.Run("get views reports every error", func(t *testing.T) {
+		req := &GetViewsRequest{Path: "Test-12-15", Year: 1999, Month: 13}
+
+		err := req.ValidateAll()
+		require.Error(t, err)
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+	})
+
+	t.Run("valid request", func(t *testing.T) {
+		req := &GetPageListRequest{AccessToken: "test-token", Limit: 10}
+		assert.NoError(t, req.ValidateAll())
+	})
+}
+
+func TestValidatePagePath(t *testing.T) {
+	valid := []string{"Test", "My-Article-12-15", "My-Article-12-15-2", "Привет-мир-03-08", "Notes_v1.2-01-01"}
+	for _, path := range valid {
+		t.Run(path, func(t *testing.T) {
+			assert.NoError(t, ValidatePagePath(path))
+			assert.NoError(t, (&GetPageRequest{Path: path}).Validate())
+		})
+	}
+
+	tests := []struct {
+		path   string
+		errMsg string
+	}{
+		{"", "path is required"},
+		{"/My-Article-12-15", `path must be a single segment without slashes, got "/My-Article-12-15"`},
+		{"Blog//Post", `path must be a single segment without slashes, got "Blog//Post"`},
+		{"https://telegra.ph/My-Article-12-15", `path must be a single segment without slashes, got "https://telegra.ph/My-Article-12-15"`},
+		{"My Article-12-15", `path must not contain whitespace, got "My Article-12-15"`},
+		{"My-Article-12-15\n", `path must not contain whitespace, got "My-Article-12-15\n"`},
+		{"..", `path must not be ".."`},
+		{"My-Article?12-15", "path contains invalid character '?'"},
+		{"My-Article-12-15#top", "path contains invalid character '#'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.EqualError(t, ValidatePagePath(tt.path), tt.errMsg)
+			assert.EqualError(t, (&GetPageRequest{Path: tt.path}).Validate(), tt.errMsg)
+
+			edit := &EditPageRequest{AccessToken: "test-token", Path: tt.path, Title: "Title", Content: []Node{{Tag: "p"}}}
+			assert.EqualError(t, edit.Validate(), tt.errMsg)
+		})
+	}
+}
+
 func TestGetPageListRequestValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -355,6 +469,87 @@ func TestGetViewsRequestValidation(t *testing.T) {
 	}
 }
 
+func TestContentBuilderAddParagraphs(t *testing.T) {
+	texts := []string{"first", "", "second", "third"}
+
+	t.Run("skips empty texts", func(t *testing.T) {
+		content := NewContentBuilder().AddParagraphs(texts...).Build()
+
+		require.Len(t, content, 3)
+		for i, want := range []string{"first", "second", "third"} {
+			assert.Equal(t, "p", content[i].Tag)
+			assert.Equal(t, want, content[i].Children[0].(Node).Content)
+		}
+	})
+
+	t.Run("keeps empty texts", func(t *testing.T) {
+		content := NewContentBuilder().AddParagraphsKeepEmpty(texts...).Build()
+
+		require.Len(t, content, 4)
+		for i, want := range texts {
+			assert.Equal(t, "p", content[i].Tag)
+			assert.Equal(t, want, content[i].Children[0].(Node).Content)
+		}
+	})
+}
+
+func TestContentBuilderAddQuoteWithCite(t *testing.T) {
+	content := NewContentBuilder().
+		AddQuoteWithCite("Simplicity is prerequisite for reliability.", "Edsger W. Dijkstra").
+		AddQuoteWithCite("Anonymous quote", "").
+		Build()
+
+	require.Len(t, content, 2)
+
+	quote := content[0]
+	assert.Equal(t, "blockquote", quote.Tag)
+	require.Len(t, quote.Children, 3)
+	assert.Equal(t, "Simplicity is prerequisite for reliability.", quote.Children[0].(Node).Content)
+	assert.Equal(t, "br", quote.Children[1].(Node).Tag)
+	cite := quote.Children[2].(Node)
+	assert.Equal(t, "em", cite.Tag)
+	assert.Equal(t, "— Edsger W. Dijkstra", cite.Children[0].(Node).Content)
+
+	assert.Equal(t, NewContentBuilder().AddBlockquote("Anonymous quote").Build()[0], content[1])
+	assert.NoError(t, ValidateContent(content))
+}
+
+func TestContentBuilderAddSectionByline(t *testing.T) {
+	content := NewContentBuilder().
+		AddHeading("Part one", 3).
+		AddSectionByline("Jane Doe").
+		AddParagraph("Text").
+		AddSectionByline("").
+		Build()
+
+	require.Len(t, content, 3)
+	assert.Equal(t, Node{
+		Tag: "p",
+		Children: []interface{}{
+			Node{Tag: "em", Children: []interface{}{Node{Content: "By Jane Doe"}}},
+		},
+	}, content[1])
+	assert.Equal(t, "p", content[2].Tag)
+	assert.NoError(t, ValidateContent(content))
+}
+
+func TestContentBuilderAddInlineCode(t *testing.T) {
+	content := NewContentBuilder().
+		AddInlineCode("Ctrl+C").
+		AddCodeBlock("go test ./...").
+		Build()
+
+	require.Len(t, content, 2)
+	assert.Equal(t, Node{
+		Tag: "p",
+		Children: []interface{}{
+			Node{Tag: "code", Children: []interface{}{Node{Content: "Ctrl+C"}}},
+		},
+	}, content[0])
+	assert.Equal(t, "pre", content[1].Tag)
+	assert.NoError(t, ValidateContent(content))
+}
+
 func TestContentBuilder(t *testing.T) {
 	t.Run("build simple content", func(t *testing.T) {
 		content := NewContentBuilder().
@@ -404,6 +599,73 @@ func TestContentBuilder(t *testing.T) {
 		assert.Contains(t, str, "Hello")
 		assert.Contains(t, str, "World")
 	})
+
+	t.Run("string representation of deeply nested content", func(t *testing.T) {
+		node := Node{Content: "leaf"}
+		for i := 0; i < 100000; i++ {
+			node = Node{Tag: "p", Children: []interface{}{node}}
+		}
+
+		assert.Equal(t, "leaf", nodeToString(node))
+	})
+}
+
+func TestNormalizeAuthorURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+		wantErr  bool
+	}{
+		{"https://example.com/jane", "https://example.com/jane", false},
+		{"https://例え.jp", "https://xn--r8jz45g.jp", false},
+		{"HTTPS://例え.JP:8443/ページ?q=1", "https://xn--r8jz45g.jp:8443/%E3%83%9A%E3%83%BC%E3%82%B8?q=1", false},
+		{"https://café.example.com/путь", "https://xn--caf-dma.example.com/%D0%BF%D1%83%D1%82%D1%8C", false},
+		{"https://Bücher.de", "https://xn--bcher-kva.de", false},
+		{"https://[::1]:8080/", "https://[::1]:8080/", false},
+		{"ftp://例え.jp", "", true},
+		{"https://", "", true},
+		{"例え.jp", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			normalized, err := NormalizeAuthorURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+
+	t.Run("validators", func(t *testing.T) {
+		account := &CreateAccountRequest{ShortName: "Test", AuthorURL: "https://例え.jp"}
+		require.NoError(t, account.Validate())
+		assert.Equal(t, "https://xn--r8jz45g.jp", account.AuthorURL)
+
+		edit := &EditAccountInfoRequest{AccessToken: "test-token", AuthorURL: "https://café.example.com"}
+		require.NoError(t, edit.Validate())
+		assert.Equal(t, "https://xn--caf-dma.example.com", edit.AuthorURL)
+
+		page := &CreatePageRequest{
+			AccessToken: "test-token",
+			Title:       "Test",
+			AuthorURL:   "https://例え.jp/著者",
+			Content:     []Node{{Tag: "p", Children: []interface{}{"Hello"}}},
+		}
+		require.NoError(t, page.Validate())
+		assert.Equal(t, "https://xn--r8jz45g.jp/%E8%91%97%E8%80%85", page.AuthorURL)
+
+		invalid := &EditPageRequest{
+			AccessToken: "test-token",
+			Path:        "Test-12-15",
+			Title:       "Test",
+			AuthorURL:   "mailto:jane@例え.jp",
+			Content:     []Node{{Tag: "p", Children: []interface{}{"Hello"}}},
+		}
+		assert.EqualError(t, invalid.Validate(), "author_url must be a valid URL")
+	})
 }
 
 func TestIsValidURL(t *testing.T) {
@@ -428,6 +690,42 @@ func TestIsValidURL(t *testing.T) {
 	}
 }
 
+func TestParseAuthURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		authURL string
+		token   string
+		errMsg  string
+	}{
+		{name: "well-formed", authURL: "https://edit.telegra.ph/auth/lu7uwdVT4QnQJd5P7yWCBRQKGJH34Vtjs7QtG3yEFO", token: "lu7uwdVT4QnQJd5P7yWCBRQKGJH34Vtjs7QtG3yEFO"},
+		{name: "http scheme", authURL: "http://edit.telegra.ph/auth/abc", errMsg: "invalid auth URL: must start with https://edit.telegra.ph/auth/"},
+		{name: "wrong host", authURL: "https://telegra.ph/auth/abc", errMsg: "invalid auth URL: must start with https://edit.telegra.ph/auth/"},
+		{name: "wrong path", authURL: "https://edit.telegra.ph/login/abc", errMsg: "invalid auth URL: path must be /auth/<token>"},
+		{name: "missing token", authURL: "https://edit.telegra.ph/auth/", errMsg: "invalid auth URL: path must be /auth/<token>"},
+		{name: "extra segment", authURL: "https://edit.telegra.ph/auth/abc/def", errMsg: "invalid auth URL: path must be /auth/<token>"},
+		{name: "empty", authURL: "", errMsg: "invalid auth URL: must start with https://edit.telegra.ph/auth/"},
+		{name: "unparsable", authURL: "https://edit.telegra.ph/auth/%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ParseAuthURL(tt.authURL)
+			account := Account{AuthURL: tt.authURL}
+			if tt.token == "" {
+				require.Error(t, err)
+				if tt.errMsg != "" {
+					assert.EqualError(t, err, tt.errMsg)
+				}
+				assert.Empty(t, account.AuthToken())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+			assert.Equal(t, tt.token, account.AuthToken())
+		})
+	}
+}
+
 func TestAPIError(t *testing.T) {
 	t.Run("with code", func(t *testing.T) {
 		err := &APIError{
@@ -444,3 +742,482 @@ func TestAPIError(t *testing.T) {
 		assert.Equal(t, "Telegraph API error: Something went wrong", err.Error())
 	})
 }
+
+func TestParseErrorKind(t *testing.T) {
+	tests := []struct {
+		description string
+		kind        ErrorKind
+	}{
+		{"PAGE_NOT_FOUND", ErrorKindPageNotFound},
+		{"TITLE_REQUIRED", ErrorKindTitleRequired},
+		{"CONTENT_TOO_BIG", ErrorKindContentTooBig},
+		{"ACCESS_TOKEN_INVALID", ErrorKindAccessTokenInvalid},
+		{"FLOOD_WAIT_5", ErrorKindFloodWait},
+		{"SOMETHING_ELSE", ErrorKindUnknown},
+		{"", ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.kind, ParseErrorKind(tt.description))
+		})
+	}
+
+	t.Run("string representation", func(t *testing.T) {
+		assert.Equal(t, "PAGE_NOT_FOUND", ErrorKindPageNotFound.String())
+		assert.Equal(t, "FLOOD_WAIT", ErrorKindFloodWait.String())
+		assert.Equal(t, "UNKNOWN", ErrorKindUnknown.String())
+	})
+
+	t.Run("flood wait", func(t *testing.T) {
+		wait, ok := (&APIError{Description: "FLOOD_WAIT_30"}).FloodWait()
+		assert.True(t, ok)
+		assert.Equal(t, 30*time.Second, wait)
+
+		_, ok = (&APIError{Description: "FLOOD_WAIT_X"}).FloodWait()
+		assert.False(t, ok)
+		_, ok = (&APIError{Description: "PAGE_NOT_FOUND"}).FloodWait()
+		assert.False(t, ok)
+	})
+}
+
+func TestRenderTemplate(t *testing.T) {
+	template := []Node{
+		{Tag: "h3", Children: []interface{}{"Hello, {{name}}!"}},
+		{Tag: "p", Children: []interface{}{
+			Node{Content: "Issue {{ issue }} of {{missing}}"},
+			Node{
+				Tag:      "a",
+				Attrs:    map[string]string{"href": "https://example.com/{{name}}"},
+				Children: []interface{}{Node{Tag: "strong", Children: []interface{}{"Read {{name}}"}}},
+			},
+		}},
+	}
+	data := map[string]string{"name": "Alice", "issue": "42"}
+
+	t.Run("substitutes text in nested nodes", func(t *testing.T) {
+		content := RenderTemplate(template, data)
+
+		assert.Equal(t, "Hello, Alice!", content[0].Children[0])
+		assert.Equal(t, "Issue 42 of {{missing}}", content[1].Children[0].(Node).Content)
+
+		link := content[1].Children[1].(Node)
+		assert.Equal(t, "https://example.com/{{name}}", link.Attrs["href"])
+		assert.Equal(t, "Read Alice", link.Children[0].(Node).Children[0])
+	})
+
+	t.Run("substitutes attributes when opted in", func(t *testing.T) {
+		content := RenderTemplateWithAttrs(template, data)
+
+		assert.Equal(t, "https://example.com/Alice", content[1].Children[1].(Node).Attrs["href"])
+	})
+
+	t.Run("leaves the template untouched", func(t *testing.T) {
+		RenderTemplateWithAttrs(template, data)
+
+		assert.Equal(t, "Hello, {{name}}!", template[0].Children[0])
+		assert.Equal(t, "https://example.com/{{name}}", template[1].Children[1].(Node).Attrs["href"])
+	})
+
+	t.Run("content builder", func(t *testing.T) {
+		content := NewContentBuilder().
+			AddParagraph("Intro").
+			AddTemplate(template, data).
+			Build()
+
+		assert.Len(t, content, 3)
+		assert.Equal(t, "Hello, Alice!", content[1].Children[0])
+	})
+
+	t.Run("node pointers and JSON objects", func(t *testing.T) {
+		link := &Node{Tag: "a", Attrs: map[string]string{"href": "/{{name}}"}, Children: []interface{}{"Hi {{name}}"}}
+		decoded := map[string]interface{}{
+			"tag":      "em",
+			"attrs":    map[string]interface{}{"href": "/{{issue}}"},
+			"children": []interface{}{"Issue {{issue}}"},
+		}
+		template := []Node{{Tag: "p", Children: []interface{}{link, decoded}}}
+
+		content := RenderTemplateWithAttrs(template, data)
+		require.Len(t, content[0].Children, 2)
+		assert.Equal(t, Node{Tag: "a", Attrs: map[string]string{"href": "/Alice"}, Children: []interface{}{"Hi Alice"}}, content[0].Children[0])
+		assert.Equal(t, Node{Tag: "em", Attrs: map[string]string{"href": "/42"}, Children: []interface{}{"Issue 42"}}, content[0].Children[1])
+
+		// The template is neither changed nor shared with the result
+		content[0].Children[0].(Node).Attrs["href"] = "changed"
+		content[0].Children[1].(Node).Children[0] = "changed"
+		assert.Equal(t, "/{{name}}", link.Attrs["href"])
+		assert.Equal(t, "Hi {{name}}", link.Children[0])
+		assert.Equal(t, "Issue {{issue}}", decoded["children"].([]interface{})[0])
+	})
+}
+
+func TestDumpContent(t *testing.T) {
+	content := []Node{
+		{Tag: "p", Children: []interface{}{
+			"Hello, ",
+			Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com", "target": "_blank"}, Children: []interface{}{
+				Node{Tag: "strong", Children: []interface{}{"world"}},
+			}},
+		}},
+		{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+		{Tag: "pre", Children: []interface{}{Node{Content: strings.Repeat("x", 50)}}},
+	}
+
+	expected := `p
+  "Hello, "
+  a href="https://example.com" target="_blank"
+    strong
+      "world"
+img src="/file/image.jpg"
+pre
+  "` + strings.Repeat("x", 40) + `"...
+`
+	assert.Equal(t, expected, DumpContent(content))
+
+	var buf strings.Builder
+	require.NoError(t, FdumpContent(&buf, content))
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestContentByteSize(t *testing.T) {
+	t.Run("matches marshaled length", func(t *testing.T) {
+		builder := NewContentBuilder().
+			AddParagraph("Hello, <World> & \"friends\"").
+			AddCodeBlock("ünïcödé")
+
+		data, err := json.Marshal(builder.Build())
+		require.NoError(t, err)
+		assert.Equal(t, len(data), builder.ByteSize())
+		assert.Equal(t, len(data), ContentByteSize(builder.Build()))
+	})
+
+	t.Run("near limit", func(t *testing.T) {
+		builder := NewContentBuilder().AddParagraph(strings.Repeat("a", MaxContentSize/2))
+		assert.False(t, builder.NearLimit())
+
+		builder.AddParagraph(strings.Repeat("a", MaxContentSize/2))
+		assert.True(t, builder.NearLimit())
+	})
+}
+
+func TestSplitContent(t *testing.T) {
+	content := NewContentBuilder().
+		AddParagraph("One").
+		AddLink("Two", "https://example.com").
+		AddParagraph("Three").
+		Build()
+
+	t.Run("split at zero", func(t *testing.T) {
+		head, tail := SplitContent(content, 0)
+		assert.Empty(t, head)
+		assert.Equal(t, content, tail)
+	})
+
+	t.Run("split in the middle", func(t *testing.T) {
+		head, tail := SplitContent(content, 2)
+		assert.Equal(t, content[:2], head)
+		assert.Equal(t, content[2:], tail)
+	})
+
+	t.Run("split beyond length", func(t *testing.T) {
+		head, tail := SplitContent(content, 10)
+		assert.Equal(t, content, head)
+		assert.Empty(t, tail)
+
+		head, tail = SplitContent(content, -1)
+		assert.Empty(t, head)
+		assert.Equal(t, content, tail)
+	})
+
+	t.Run("deep copies nodes", func(t *testing.T) {
+		head, _ := SplitContent(content, 2)
+
+		link := head[1].Children[0].(Node)
+		link.Attrs["href"] = "https://changed.example.com"
+		link.Children[0] = Node{Content: "Changed"}
+		head[0].Children[0] = Node{Content: "Changed"}
+
+		original := content[1].Children[0].(Node)
+		assert.Equal(t, "https://example.com", original.Attrs["href"])
+		assert.Equal(t, "Two", original.Children[0].(Node).Content)
+		assert.Equal(t, "One", content[0].Children[0].(Node).Content)
+	})
+}
+
+func TestPageContentJSON(t *testing.T) {
+	page := &Page{
+		Content: NewContentBuilder().
+			AddHeading("Title", 3).
+			AddLink("Example", "https://example.com").
+			AddImage("https://example.com/image.jpg").
+			AddLineBreak().
+			Build(),
+	}
+
+	data, err := page.ContentJSON()
+	require.NoError(t, err)
+
+	// The golden file uses Telegraph's content format, in which text is a bare string
+	golden, err := os.ReadFile("testdata/content.golden.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(golden), string(data))
+
+	// The content decodes back to the same nodes
+	var decoded []Node
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, NodesEqual(page.Content, decoded))
+
+	// ContentJSON matches the content sent by CreatePage
+	request, err := json.Marshal(&CreatePageRequest{Content: page.Content})
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `"content":`+string(data))
+}
+
+func TestNodesEqual(t *testing.T) {
+	a := []Node{{Tag: "p", Children: []interface{}{"Hello, ", Node{Content: "World"}}}}
+
+	assert.True(t, NodesEqual(a, []Node{{Tag: "P", Attrs: map[string]string{}, Children: []interface{}{"Hello, World"}}}))
+	assert.True(t, NodesEqual([]Node{{Tag: "div", Children: []interface{}{Node{Tag: "p", Children: a[0].Children}}}},
+		[]Node{{Tag: "div", Children: []interface{}{map[string]interface{}{"tag": "p", "children": []interface{}{"Hello, World"}}}}}))
+	assert.False(t, NodesEqual(a, []Node{{Tag: "p", Children: []interface{}{"Hello, world"}}}))
+	assert.False(t, NodesEqual(a, []Node{{Tag: "p", Attrs: map[string]string{"id": "x"}, Children: []interface{}{"Hello, World"}}}))
+	assert.False(t, NodesEqual(a, append(a, Node{Tag: "hr"})))
+}
+
+func TestNodeJSON(t *testing.T) {
+	content := []Node{
+		{Content: "Top-level text"},
+		{Tag: "p", Content: "Hello, ", Children: []interface{}{
+			Node{Tag: "b", Children: []interface{}{"World"}},
+			&Node{Content: "!"},
+		}},
+		{Tag: "hr"},
+	}
+
+	data, err := json.Marshal(content)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["Top-level text",{"tag":"p","children":["Hello, ",{"tag":"b","children":["World"]},"!"]},{"tag":"hr"}]`, string(data))
+
+	var decoded []Node
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Node{Content: "Top-level text"}, decoded[0])
+	assert.Equal(t, Node{Tag: "hr"}, decoded[2])
+	assert.True(t, NodesEqual(content, decoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`[42]`), &decoded))
+}
+
+func TestNodeMarshalAttrOrder(t *testing.T) {
+	build := func() []Node {
+		// Insert the attributes in a different order each time
+		attrs := make(map[string]string)
+		for _, key := range []string{"src", "width", "alt", "height", "data-id"} {
+			attrs[key] = key + "-value"
+		}
+		return []Node{{Tag: "p", Children: []interface{}{Node{Tag: "img", Attrs: attrs}}}}
+	}
+
+	first, err := json.Marshal(build())
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		again, err := json.Marshal(build())
+		require.NoError(t, err)
+		assert.Equal(t, string(first), string(again))
+	}
+
+	assert.Contains(t, string(first),
+		`"attrs":{"alt":"alt-value","data-id":"data-id-value","height":"height-value","src":"src-value","width":"width-value"}`)
+}
+
+func TestContentChecksum(t *testing.T) {
+	keys := []string{"href", "src", "data-a", "data-b", "data-c", "data-d", "data-e", "data-f"}
+	tree := func(order []string) []Node {
+		attrs := make(map[string]string, len(order))
+		for _, key := range order {
+			attrs[key] = "value-" + key
+		}
+		return []Node{{Tag: "p", Children: []interface{}{
+			"Hello, ",
+			Node{Tag: "a", Attrs: attrs, Children: []interface{}{"link"}},
+		}}}
+	}
+
+	want, err := ContentChecksum(tree(keys))
+	require.NoError(t, err)
+	assert.Len(t, want, 64)
+
+	t.Run("attribute order", func(t *testing.T) {
+		reversed := make([]string, len(keys))
+		for i, key := range keys {
+			reversed[len(keys)-1-i] = key
+		}
+		for i := 0; i < 10; i++ {
+			got, err := ContentChecksum(tree(reversed))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("equivalent representations", func(t *testing.T) {
+		attrs := map[string]interface{}{}
+		for _, key := range keys {
+			attrs[key] = "value-" + key
+		}
+		decoded := []Node{{Tag: "P", Children: []interface{}{
+			Node{Content: "Hello"},
+			", ",
+			map[string]interface{}{"tag": "a", "attrs": attrs, "children": []interface{}{"link"}},
+		}}}
+
+		got, err := ContentChecksum(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("different content", func(t *testing.T) {
+		changed := tree(keys)
+		changed[0].Children[0] = "Goodbye, "
+
+		got, err := ContentChecksum(changed)
+		require.NoError(t, err)
+		assert.NotEqual(t, want, got)
+	})
+}
+
+func TestCoalesceInline(t *testing.T) {
+	strong := func(children ...interface{}) Node { return Node{Tag: "strong", Children: children} }
+	link := func(href string, children ...interface{}) Node {
+		return Node{Tag: "a", Attrs: map[string]string{"href": href}, Children: children}
+	}
+
+	t.Run("adjacent bold runs", func(t *testing.T) {
+		content := []Node{{Tag: "p", Children: []interface{}{"Say ", strong("hel"), strong("lo"), strong(Node{Tag: "em", Children: []interface{}{"!"}}), " now"}}}
+		assert.Equal(t, []Node{{Tag: "p", Children: []interface{}{
+			"Say ", strong("hello", Node{Tag: "em", Children: []interface{}{"!"}}), " now",
+		}}}, CoalesceInline(content))
+	})
+
+	t.Run("nested runs merge after their parents", func(t *testing.T) {
+		em := func(text string) Node { return Node{Tag: "em", Children: []interface{}{text}} }
+		content := []Node{{Tag: "p", Children: []interface{}{strong(em("a")), strong(em("b"))}}}
+		assert.Equal(t, []Node{{Tag: "p", Children: []interface{}{strong(em("ab"))}}}, CoalesceInline(content))
+	})
+
+	t.Run("mixed runs are kept", func(t *testing.T) {
+		content := []Node{
+			{Tag: "p", Children: []interface{}{
+				strong("a"), " ", strong("b"),
+				Node{Tag: "em", Children: []interface{}{"c"}}, strong("d"),
+				link("https://a.example", "e"), link("https://b.example", "f"),
+			}},
+			{Tag: "p", Children: []interface{}{strong("g")}},
+			{Tag: "p", Children: []interface{}{strong("h")}},
+		}
+		assert.Equal(t, content, CoalesceInline(content))
+	})
+
+	t.Run("same links merge", func(t *testing.T) {
+		content := []Node{{Tag: "p", Children: []interface{}{link("https://a.example", "one "), link("https://a.example", "two")}}}
+		assert.Equal(t, []Node{{Tag: "p", Children: []interface{}{link("https://a.example", "one two")}}}, CoalesceInline(content))
+	})
+
+	t.Run("does not modify its input", func(t *testing.T) {
+		content := []Node{{Tag: "p", Children: []interface{}{strong("a"), strong("b")}}}
+		CoalesceInline(content)
+		assert.Equal(t, []Node{{Tag: "p", Children: []interface{}{strong("a"), strong("b")}}}, content)
+	})
+}
+
+func TestStripDataURIImages(t *testing.T) {
+	dataURI := "data:image/png;base64,iVBORw0KGgo="
+	content := []Node{
+		{Tag: "img", Attrs: map[string]string{"src": dataURI}},
+		{Tag: "p", Children: []interface{}{
+			"Inline ",
+			Node{Tag: "img", Attrs: map[string]string{"src": " DATA:image/gif;base64,R0lGOD="}},
+			map[string]interface{}{"tag": "img", "attrs": map[string]interface{}{"src": dataURI}},
+		}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": dataURI}},
+		}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "img", Attrs: map[string]string{"src": "https://example.com/photo.jpg"}},
+			Node{Tag: "figcaption", Children: []interface{}{"Photo"}},
+		}},
+		{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+	}
+	original := CloneContent(content)
+
+	t.Run("strip", func(t *testing.T) {
+		assert.Equal(t, []Node{
+			{Tag: "p", Children: []interface{}{"Inline "}},
+			{Tag: "figure", Children: []interface{}{
+				Node{Tag: "img", Attrs: map[string]string{"src": "https://example.com/photo.jpg"}},
+				Node{Tag: "figcaption", Children: []interface{}{"Photo"}},
+			}},
+			{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}},
+		}, StripDataURIImages(content))
+		assert.Equal(t, original, content)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		placeholder := Node{Tag: "em", Children: []interface{}{"[image]"}}
+
+		result := ReplaceDataURIImages(content, placeholder)
+		require.Len(t, result, 5)
+		assert.Equal(t, placeholder, result[0])
+		assert.Equal(t, []interface{}{"Inline ", placeholder, placeholder}, result[1].Children)
+		assert.Equal(t, []interface{}{placeholder}, result[2].Children)
+		assert.Equal(t, content[3], result[3])
+		assert.Equal(t, content[4], result[4])
+	})
+}
+
+func TestSplitByHeading(t *testing.T) {
+	p := func(text string) Node { return Node{Tag: "p", Children: []interface{}{text}} }
+	h3 := func(text string) Node { return Node{Tag: "h3", Children: []interface{}{text}} }
+	h4 := func(text string) Node { return Node{Tag: "h4", Children: []interface{}{text}} }
+
+	content := []Node{
+		p("Preface"),
+		h3("Chapter 1"), p("One"), h4("Section 1.1"), p("One one"),
+		h3("Chapter 2"), p("Two"),
+		h4("Section 2.1"),
+	}
+
+	t.Run("level 3", func(t *testing.T) {
+		assert.Equal(t, [][]Node{
+			{p("Preface")},
+			{h3("Chapter 1"), p("One"), h4("Section 1.1"), p("One one")},
+			{h3("Chapter 2"), p("Two"), h4("Section 2.1")},
+		}, SplitByHeading(content, 3))
+	})
+
+	t.Run("level 4", func(t *testing.T) {
+		assert.Equal(t, [][]Node{
+			{p("Preface")},
+			{h3("Chapter 1"), p("One")},
+			{h4("Section 1.1"), p("One one")},
+			{h3("Chapter 2"), p("Two")},
+			{h4("Section 2.1")},
+		}, SplitByHeading(content, 4))
+	})
+
+	t.Run("starts with a heading", func(t *testing.T) {
+		parts := SplitByHeading(content[1:], 3)
+		require.Len(t, parts, 2)
+		assert.Equal(t, h3("Chapter 1"), parts[0][0])
+	})
+
+	t.Run("no headings", func(t *testing.T) {
+		assert.Equal(t, [][]Node{{p("Preface")}}, SplitByHeading([]Node{p("Preface")}, 3))
+		assert.Nil(t, SplitByHeading(nil, 3))
+	})
+
+	t.Run("parts are copies", func(t *testing.T) {
+		parts := SplitByHeading(content, 3)
+		parts[0][0].Children[0] = "changed"
+		assert.Equal(t, "Preface", content[0].Children[0])
+	})
+}
@@ -0,0 +1,288 @@
+package telegraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+)
+
+// DefaultUploadURL is the telegra.ph endpoint used to upload images and videos
+const DefaultUploadURL = "https://telegra.ph/upload"
+
+// MaxUploadSize is the largest file, in bytes, that UploadFile reads and
+// telegra.ph accepts. Larger files are rejected before anything is uploaded.
+const MaxUploadSize = 5 << 20
+
+// ErrUploadTooLarge is returned for files larger than MaxUploadSize
+var ErrUploadTooLarge = errors.New("file exceeds maximum upload size")
+
+// ImageFetcher opens the image referenced by an img tag's src attribute.
+// It returns the image data and a file name used to determine the content type.
+// If the returned reader is an io.Closer, it is closed after the upload.
+type ImageFetcher func(src string) (io.Reader, string, error)
+
+// WithUploadURL sets a custom URL for file uploads
+func WithUploadURL(uploadURL string) ClientOption {
+	return func(c *Client) {
+		c.uploadURL = uploadURL
+	}
+}
+
+// DefaultUploadFieldName is the multipart form field telegra.ph reads uploaded files from
+const DefaultUploadFieldName = "file"
+
+// UploadOption configures a single UploadFile call
+type UploadOption func(*uploadOptions)
+
+// uploadOptions holds the settings of an UploadFile call
+type uploadOptions struct {
+	fieldName   string
+	contentType string
+}
+
+// WithUploadFieldName sets the multipart form field holding the file
+// (default: DefaultUploadFieldName), for upload endpoints of mirrors that
+// expect another name
+func WithUploadFieldName(name string) UploadOption {
+	return func(o *uploadOptions) {
+		o.fieldName = name
+	}
+}
+
+// WithUploadContentType sets the content type of the file, instead of deriving
+// it from the file name or data
+func WithUploadContentType(contentType string) UploadOption {
+	return func(o *uploadOptions) {
+		o.contentType = contentType
+	}
+}
+
+// uploadResult is a single entry of a successful upload response
+type uploadResult struct {
+	Src string `json:"src"`
+}
+
+// uploadError is the response returned when an upload is rejected
+type uploadError struct {
+	Error string `json:"error"`
+}
+
+// UploadFile uploads an image or video to telegra.ph
+//
+// The file is sent in the multipart form field telegra.ph expects, and its
+// content type is derived from the file name's extension, falling back to
+// sniffing the data; both can be overridden with UploadOptions. Returns the
+// path of the uploaded file (e.g. "/file/abc.jpg"), which can be used as the
+// src of img and video nodes. At most MaxUploadSize bytes are read from r; a
+// larger file fails with ErrUploadTooLarge.
+//
+// Example:
+//
+//	f, _ := os.Open("photo.jpg")
+//	defer f.Close()
+//	src, err := client.UploadFile(ctx, f, "photo.jpg")
+func (c *Client) UploadFile(ctx context.Context, r io.Reader, filename string, opts ...UploadOption) (string, error) {
+	options := uploadOptions{fieldName: DefaultUploadFieldName}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxUploadSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > MaxUploadSize {
+		return "", fmt.Errorf("%w of %d bytes", ErrUploadTooLarge, MaxUploadSize)
+	}
+
+	contentType := options.contentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(options.fieldName), escapeQuotes(path.Base(filename))))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+
+	if err := c.waitRateLimit(ctx); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRateLimitWait, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.uploadURL, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", c.accept)
+	req.Header.Set("User-Agent", "telegraph-go-sdk/1.0.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", &redactedError{err: err})
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var results []uploadResult
+	if err := json.Unmarshal(respBody, &results); err == nil && len(results) > 0 && results[0].Src != "" {
+		return results[0].Src, nil
+	}
+
+	var uploadErr uploadError
+	if err := json.Unmarshal(respBody, &uploadErr); err == nil && uploadErr.Error != "" {
+		return "", &APIError{
+			Code:        resp.StatusCode,
+			Description: uploadErr.Error,
+			Kind:        ParseErrorKind(uploadErr.Error),
+		}
+	}
+
+	return "", &APIError{
+		Code:        resp.StatusCode,
+		Description: string(respBody),
+		Kind:        ErrorKindUnknown,
+	}
+}
+
+// escapeQuotes escapes a value for use in a quoted header parameter
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
+
+// fetchImage downloads an http(s) image; it is the default ImageFetcher
+func (c *Client) fetchImage(ctx context.Context, src string) (io.Reader, string, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return nil, "", fmt.Errorf("unsupported image source: %s", src)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", src, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("failed to fetch image: received status code %d", resp.StatusCode)
+	}
+	// Fail before downloading an image too large to upload
+	if resp.ContentLength > MaxUploadSize {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("failed to fetch image: %w of %d bytes", ErrUploadTooLarge, MaxUploadSize)
+	}
+
+	filename := path.Base(req.URL.Path)
+	if exts, _ := mime.ExtensionsByType(resp.Header.Get("Content-Type")); path.Ext(filename) == "" && len(exts) > 0 {
+		filename += exts[0]
+	}
+	return resp.Body, filename, nil
+}
+
+// isTelegraphFile reports whether src already points to a file hosted on telegra.ph
+func isTelegraphFile(src string) bool {
+	return strings.HasPrefix(src, "/file/") || strings.HasPrefix(src, "https://telegra.ph/file/")
+}
+
+// uploadImages uploads the images referenced by img nodes and rewrites their src
+// to the uploaded path. Each distinct src is uploaded once.
+func (c *Client) uploadImages(ctx context.Context, nodes []Node, opts *HTMLToPageOptions) error {
+	fetch := opts.ImageFetcher
+	if fetch == nil {
+		fetch = func(src string) (io.Reader, string, error) {
+			return c.fetchImage(ctx, src)
+		}
+	}
+
+	uploaded := make(map[string]string)
+	upload := func(src string) (string, error) {
+		if dst, ok := uploaded[src]; ok {
+			return dst, nil
+		}
+
+		r, filename, err := fetch(src)
+		if err != nil {
+			return "", err
+		}
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		dst, err := c.UploadFile(ctx, r, filename)
+		if err != nil {
+			return "", err
+		}
+		uploaded[src] = dst
+		return dst, nil
+	}
+
+	var rewrite func(node *Node) error
+	rewrite = func(node *Node) error {
+		if node.Tag == "img" {
+			if src := node.Attrs["src"]; src != "" && !isTelegraphFile(src) {
+				dst, err := upload(src)
+				if err != nil && !opts.IgnoreUploadErrors {
+					return fmt.Errorf("failed to upload image %s: %w", src, err)
+				}
+				if err == nil {
+					node.Attrs["src"] = dst
+				}
+			}
+		}
+
+		for i, child := range node.Children {
+			if childNode, ok := child.(*Node); ok && childNode != nil {
+				if err := rewrite(childNode); err != nil {
+					return err
+				}
+				continue
+			}
+			// Node values and JSON objects are replaced by the rewritten Node
+			if childNode, ok := asNode(child); ok {
+				if err := rewrite(&childNode); err != nil {
+					return err
+				}
+				node.Children[i] = childNode
+			}
+		}
+		return nil
+	}
+
+	for i := range nodes {
+		if err := rewrite(&nodes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUploadServer serves an upload endpoint that rejects files named "bad.*"
+func newUploadServer(t *testing.T, uploads *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		if uploads != nil {
+			*uploads = append(*uploads, header.Filename+":"+header.Header.Get("Content-Type")+":"+string(data))
+		}
+
+		if strings.HasPrefix(header.Filename, "bad.") {
+			json.NewEncoder(w).Encode(map[string]string{"error": "File type invalid"})
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]string{{"src": "/file/" + header.Filename}})
+	}))
+}
+
+func TestClientUploadFile(t *testing.T) {
+	var uploads []string
+	server := newUploadServer(t, &uploads)
+	defer server.Close()
+
+	client := NewClient(WithUploadURL(server.URL))
+
+	t.Run("success", func(t *testing.T) {
+		src, err := client.UploadFile(context.Background(), strings.NewReader("png-data"), "image.png")
+		require.NoError(t, err)
+		assert.Equal(t, "/file/image.png", src)
+		assert.Equal(t, []string{"image.png:image/png:png-data"}, uploads)
+	})
+
+	t.Run("rejected upload", func(t *testing.T) {
+		_, err := client.UploadFile(context.Background(), strings.NewReader("data"), "bad.exe")
+		require.Error(t, err)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "File type invalid", apiErr.Description)
+	})
+}
+
+func TestClientUploadSizeLimit(t *testing.T) {
+	var uploads []string
+	uploadServer := newUploadServer(t, &uploads)
+	defer uploadServer.Close()
+
+	large := strings.Repeat("x", MaxUploadSize+1)
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/declared.jpg" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(large)))
+		}
+		io.WriteString(w, large)
+	}))
+	defer imageServer.Close()
+
+	client := NewClient(WithUploadURL(uploadServer.URL))
+	ctx := context.Background()
+
+	t.Run("file too large", func(t *testing.T) {
+		_, err := client.UploadFile(ctx, strings.NewReader(large), "large.jpg")
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+
+		src, err := client.UploadFile(ctx, strings.NewReader(large[1:]), "max.jpg")
+		require.NoError(t, err)
+		assert.Equal(t, "/file/max.jpg", src)
+		assert.Len(t, uploads, 1)
+	})
+
+	t.Run("declared image size checked before reading", func(t *testing.T) {
+		_, _, err := client.fetchImage(ctx, imageServer.URL+"/declared.jpg")
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+	})
+
+	t.Run("streamed image size checked while reading", func(t *testing.T) {
+		uploads = nil
+		nodes := []Node{{Tag: "img", Attrs: map[string]string{"src": imageServer.URL + "/streamed.jpg"}}}
+		err := client.uploadImages(ctx, nodes, &HTMLToPageOptions{})
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+		assert.Empty(t, uploads)
+	})
+}
+
+func TestUploadImagesNestedChildren(t *testing.T) {
+	var uploads []string
+	server := newUploadServer(t, &uploads)
+	defer server.Close()
+
+	client := NewClient(WithUploadURL(server.URL))
+	fetcher := func(src string) (io.Reader, string, error) {
+		return strings.NewReader("data"), src, nil
+	}
+
+	pointer := &Node{Tag: "figure", Children: []interface{}{Node{Tag: "img", Attrs: map[string]string{"src": "pointer.jpg"}}}}
+	nodes := []Node{{Tag: "div", Children: []interface{}{
+		pointer,
+		map[string]interface{}{"tag": "figure", "children": []interface{}{
+			map[string]interface{}{"tag": "img", "attrs": map[string]interface{}{"src": "object.jpg"}},
+		}},
+	}}}
+
+	require.NoError(t, client.uploadImages(context.Background(), nodes, &HTMLToPageOptions{ImageFetcher: fetcher}))
+	assert.Equal(t, "/file/pointer.jpg", pointer.Children[0].(Node).Attrs["src"])
+	figure := nodes[0].Children[1].(Node)
+	assert.Equal(t, "/file/object.jpg", figure.Children[0].(Node).Attrs["src"])
+	assert.Len(t, uploads, 2)
+}
+
+func TestClientUploadFileOptions(t *testing.T) {
+	var fields []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		fields = append(fields, part.FormName()+":"+part.FileName()+":"+part.Header.Get("Content-Type"))
+
+		json.NewEncoder(w).Encode([]map[string]string{{"src": "/file/" + part.FileName()}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithUploadURL(server.URL))
+	ctx := context.Background()
+
+	_, err := client.UploadFile(ctx, strings.NewReader("png-data"), "image.png")
+	require.NoError(t, err)
+
+	_, err = client.UploadFile(ctx, strings.NewReader("png-data"), "image.png",
+		WithUploadFieldName("upload"), WithUploadContentType("image/x-custom"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"file:image.png:image/png",
+		"upload:image.png:image/x-custom",
+	}, fields)
+}
+
+func TestConvertHTMLToPageUploadImages(t *testing.T) {
+	var uploads []string
+	server := newUploadServer(t, &uploads)
+	defer server.Close()
+
+	client := NewClient(WithUploadURL(server.URL))
+
+	html := `<html><body>
+<figure><img src="local/cat.jpg"></figure>
+<p><img src="local/cat.jpg"><img src="/file/hosted.jpg"></p>
+<figure><img src="local/bad.jpg"></figure>
+</body></html>`
+
+	fetcher := func(src string) (io.Reader, string, error) {
+		if src == "missing.jpg" {
+			return nil, "", errors.New("not found")
+		}
+		return strings.NewReader("data:" + src), strings.TrimPrefix(src, "local/"), nil
+	}
+
+	t.Run("fails on upload error by default", func(t *testing.T) {
+		_, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{UploadImages: true, ImageFetcher: fetcher})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to upload image local/bad.jpg")
+		assert.Contains(t, err.Error(), "File type invalid")
+	})
+
+	t.Run("uploads and rewrites images", func(t *testing.T) {
+		uploads = nil
+		page, err := client.ConvertHTMLToPage(html, &HTMLToPageOptions{
+			UploadImages:       true,
+			ImageFetcher:       fetcher,
+			IgnoreUploadErrors: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/file/cat.jpg", page.Content[1].Children[0].(Node).Attrs["src"])
+
+		paragraph := page.Content[3]
+		assert.Equal(t, "/file/cat.jpg", paragraph.Children[0].(Node).Attrs["src"])
+		assert.Equal(t, "/file/hosted.jpg", paragraph.Children[1].(Node).Attrs["src"])
+
+		// Failed uploads keep their original source
+		assert.Equal(t, "local/bad.jpg", page.Content[5].Children[0].(Node).Attrs["src"])
+
+		// Each distinct image is uploaded once, hosted images are skipped
+		assert.Equal(t, []string{
+			"cat.jpg:image/jpeg:data:local/cat.jpg",
+			"bad.jpg:image/jpeg:data:local/bad.jpg",
+		}, uploads)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		uploads = nil
+		page, err := client.ConvertHTMLToPage(html, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "local/cat.jpg", page.Content[1].Children[0].(Node).Attrs["src"])
+		assert.Empty(t, uploads)
+	})
+}
@@ -0,0 +1,204 @@
+package telegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// supportedTags lists the tags accepted by the Telegraph API.
+// Available tags: a, aside, b, blockquote, br, code, em, figcaption, figure, h3, h4, hr, i, iframe, img, li, ol, p, pre, s, strong, u, ul, video.
+var supportedTags = map[string]bool{
+	"a": true, "aside": true, "b": true, "blockquote": true, "br": true, "code": true,
+	"em": true, "figcaption": true, "figure": true, "h3": true, "h4": true, "hr": true,
+	"i": true, "iframe": true, "img": true, "li": true, "ol": true, "p": true, "pre": true,
+	"s": true, "strong": true, "u": true, "ul": true, "video": true,
+}
+
+// blockTags lists tags that start a new block and cannot be nested in inline tags
+var blockTags = map[string]bool{
+	"aside": true, "blockquote": true, "figcaption": true, "figure": true, "h3": true, "h4": true,
+	"hr": true, "iframe": true, "li": true, "ol": true, "p": true, "pre": true, "ul": true, "video": true,
+}
+
+// inlineTags lists text-level tags that may only contain text and other inline tags
+var inlineTags = map[string]bool{
+	"a": true, "b": true, "code": true, "em": true, "i": true, "s": true, "strong": true, "u": true,
+}
+
+// mediaTags lists embedded media tags, which must sit at the top level or inside a figure
+var mediaTags = map[string]bool{
+	"img": true, "iframe": true, "video": true,
+}
+
+// ErrDryValidationDisabled is returned by DryValidateContent unless the client
+// was created with WithDryValidation
+var ErrDryValidationDisabled = errors.New("dry validation is disabled; enable it with WithDryValidation")
+
+// DryValidationTitle is the title of the pages created by DryValidateContent
+const DryValidationTitle = "Content validation"
+
+// WithDryValidation enables DryValidateContent, which creates real pages
+func WithDryValidation() ClientOption {
+	return func(c *Client) {
+		c.dryValidation = true
+	}
+}
+
+// DryValidateContent checks that the Telegraph API accepts content by creating
+// a page with it
+//
+// Unlike ValidateContent, which applies the rules known to this package, this
+// asks the API itself. Side effect: Telegraph cannot delete pages, so every call
+// leaves a page titled DryValidationTitle in the account. The page is not linked
+// from anywhere, but it is public to anyone who has its URL and it counts
+// towards the account's pages. Because of this, DryValidateContent returns
+// ErrDryValidationDisabled unless the client was created with WithDryValidation.
+// Consider using a separate account for validation.
+//
+// Example:
+//
+//	client := telegraph.NewClient(telegraph.WithDryValidation())
+//	if err := client.DryValidateContent(ctx, scratchToken, content); err != nil {
+//		log.Printf("content rejected: %v", err)
+//	}
+func (c *Client) DryValidateContent(ctx context.Context, accessToken string, nodes []Node) error {
+	if !c.dryValidation {
+		return ErrDryValidationDisabled
+	}
+
+	_, err := c.CreatePage(ctx, &CreatePageRequest{
+		AccessToken: accessToken,
+		Title:       DryValidationTitle,
+		Content:     nodes,
+	})
+	return err
+}
+
+// ValidateContent checks content against Telegraph's tag and nesting rules
+//
+// The following rules are enforced:
+//   - only tags supported by Telegraph are used
+//   - figcaption appears only directly inside figure
+//   - img, iframe and video appear only at the top level or directly inside figure
+//   - li appears only directly inside ul or ol
+//   - block tags are never nested inside inline tags such as a, strong or em
+//
+// Errors include the path of the offending node, e.g. "content[1].children[0]".
+func ValidateContent(nodes []Node) error {
+	return ValidateContentWithOptions(nodes, ContentValidationOptions{})
+}
+
+// ContentValidationOptions represents optional checks of ValidateContentWithOptions
+type ContentValidationOptions struct {
+	// MaxNodeSize is the maximum serialized size in bytes of a single node,
+	// including its children, or 0 for no limit. It catches single oversized
+	// nodes, such as a pre block holding an accidentally embedded base64 blob,
+	// in content that is otherwise within MaxContentSize.
+	MaxNodeSize int
+}
+
+// ValidateContentWithOptions is like ValidateContent but also applies the checks enabled in opts
+//
+// When a node exceeds MaxNodeSize, so do its ancestors; the error reports the
+// innermost oversized node.
+func ValidateContentWithOptions(nodes []Node, opts ContentValidationOptions) error {
+	for i, node := range nodes {
+		if err := validateNode(node, fmt.Sprintf("content[%d]", i), "", "", 1, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNode validates node and its children. parent is the tag of the direct
+// parent, and inlineAncestor is the closest inline ancestor tag, if any.
+func validateNode(node Node, path, parent, inlineAncestor string, depth int, opts ContentValidationOptions) error {
+	if depth > DefaultMaxDepth {
+		return fmt.Errorf("%s: content exceeds maximum nesting depth of %d", path, DefaultMaxDepth)
+	}
+
+	// Text nodes have no structural constraints
+	if node.Tag == "" {
+		return checkNodeSize(node, path, opts)
+	}
+
+	tag := strings.ToLower(node.Tag)
+	if !supportedTags[tag] {
+		return fmt.Errorf("%s: unsupported tag <%s>", path, node.Tag)
+	}
+
+	switch {
+	case tag == "figcaption" && parent != "figure":
+		return fmt.Errorf("%s: <figcaption> must be inside <figure>", path)
+	case mediaTags[tag] && parent != "" && parent != "figure":
+		return fmt.Errorf("%s: <%s> must be at the top level or inside <figure>, not <%s>", path, tag, parent)
+	case tag == "li" && parent != "ul" && parent != "ol":
+		return fmt.Errorf("%s: <li> must be inside <ul> or <ol>", path)
+	case blockTags[tag] && inlineAncestor != "":
+		return fmt.Errorf("%s: block tag <%s> cannot be inside inline tag <%s>", path, tag, inlineAncestor)
+	}
+
+	if inlineTags[tag] && inlineAncestor == "" {
+		inlineAncestor = tag
+	}
+
+	for i, child := range node.Children {
+		childPath := fmt.Sprintf("%s.children[%d]", path, i)
+		if text, ok := child.(string); ok {
+			if err := checkNodeSize(Node{Content: text}, childPath, opts); err != nil {
+				return err
+			}
+			continue
+		}
+		childNode, ok := asNode(child)
+		if !ok {
+			continue
+		}
+		if err := validateNode(childNode, childPath, tag, inlineAncestor, depth+1, opts); err != nil {
+			return err
+		}
+	}
+	return checkNodeSize(node, path, opts)
+}
+
+// checkNodeSize reports an error if node exceeds opts.MaxNodeSize
+func checkNodeSize(node Node, path string, opts ContentValidationOptions) error {
+	if opts.MaxNodeSize <= 0 {
+		return nil
+	}
+	if size := ContentByteSize([]Node{node}) - len("[]"); size > opts.MaxNodeSize {
+		return fmt.Errorf("%s: node size of %d bytes exceeds maximum of %d", path, size, opts.MaxNodeSize)
+	}
+	return nil
+}
+
+// asNode converts a child value into a Node. Children decoded from API
+// responses are JSON objects rather than Nodes, so both forms are accepted.
+// Plain string children are not nodes and report false.
+func asNode(child interface{}) (Node, bool) {
+	switch ch := child.(type) {
+	case Node:
+		return ch, true
+	case *Node:
+		if ch == nil {
+			return Node{}, false
+		}
+		return *ch, true
+	case map[string]interface{}:
+		var node Node
+		node.Tag, _ = ch["tag"].(string)
+		if attrs, ok := ch["attrs"].(map[string]interface{}); ok {
+			node.Attrs = make(map[string]string, len(attrs))
+			for k, v := range attrs {
+				if s, ok := v.(string); ok {
+					node.Attrs[k] = s
+				}
+			}
+		}
+		node.Children, _ = ch["children"].([]interface{})
+		return node, true
+	}
+	return Node{}, false
+}
@@ -0,0 +1,187 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []Node
+		errMsg  string
+	}{
+		{
+			name: "valid content",
+			content: []Node{
+				{Tag: "p", Children: []interface{}{"Hello, ", Node{Tag: "a", Attrs: map[string]string{"href": "https://example.com"}, Children: []interface{}{Node{Tag: "strong", Children: []interface{}{"world"}}}}}},
+				{Tag: "figure", Children: []interface{}{Node{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}}, Node{Tag: "figcaption", Children: []interface{}{"Caption"}}}},
+				{Tag: "ul", Children: []interface{}{Node{Tag: "li", Children: []interface{}{"Item"}}}},
+				{Tag: "iframe", Attrs: map[string]string{"src": "/embed/youtube?url=x"}},
+			},
+		},
+		{
+			name:    "unsupported tag",
+			content: []Node{{Tag: "table"}},
+			errMsg:  "content[0]: unsupported tag <table>",
+		},
+		{
+			name:    "figcaption outside figure",
+			content: []Node{{Tag: "p", Children: []interface{}{Node{Tag: "figcaption", Children: []interface{}{"Caption"}}}}},
+			errMsg:  "content[0].children[0]: <figcaption> must be inside <figure>",
+		},
+		{
+			name:    "top-level figcaption",
+			content: []Node{{Tag: "figcaption"}},
+			errMsg:  "content[0]: <figcaption> must be inside <figure>",
+		},
+		{
+			name:    "img inside paragraph",
+			content: []Node{{Tag: "p", Children: []interface{}{"text", Node{Tag: "img", Attrs: map[string]string{"src": "/file/image.jpg"}}}}},
+			errMsg:  "content[0].children[1]: <img> must be at the top level or inside <figure>, not <p>",
+		},
+		{
+			name:    "iframe inside blockquote",
+			content: []Node{{Tag: "blockquote", Children: []interface{}{Node{Tag: "iframe"}}}},
+			errMsg:  "content[0].children[0]: <iframe> must be at the top level or inside <figure>, not <blockquote>",
+		},
+		{
+			name:    "video inside list item",
+			content: []Node{{Tag: "ul", Children: []interface{}{Node{Tag: "li", Children: []interface{}{Node{Tag: "video"}}}}}},
+			errMsg:  "content[0].children[0].children[0]: <video> must be at the top level or inside <figure>, not <li>",
+		},
+		{
+			name:    "li outside list",
+			content: []Node{{Tag: "p", Children: []interface{}{Node{Tag: "li", Children: []interface{}{"Item"}}}}},
+			errMsg:  "content[0].children[0]: <li> must be inside <ul> or <ol>",
+		},
+		{
+			name:    "block inside link",
+			content: []Node{{Tag: "p", Children: []interface{}{Node{Tag: "a", Children: []interface{}{Node{Tag: "p", Children: []interface{}{"text"}}}}}}},
+			errMsg:  "content[0].children[0].children[0]: block tag <p> cannot be inside inline tag <a>",
+		},
+		{
+			name:    "block inside nested inline tags",
+			content: []Node{{Tag: "p", Children: []interface{}{Node{Tag: "strong", Children: []interface{}{Node{Tag: "em", Children: []interface{}{Node{Tag: "ul"}}}}}}}},
+			errMsg:  "content[0].children[0].children[0].children[0]: block tag <ul> cannot be inside inline tag <strong>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContent(tt.content)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.errMsg, err.Error())
+		})
+	}
+
+	t.Run("content decoded from JSON", func(t *testing.T) {
+		var content []Node
+		err := json.Unmarshal([]byte(`[{"tag":"p","children":[{"tag":"li","children":["Item"]}]}]`), &content)
+		require.NoError(t, err)
+
+		err = ValidateContent(content)
+		require.Error(t, err)
+		assert.Equal(t, "content[0].children[0]: <li> must be inside <ul> or <ol>", err.Error())
+	})
+}
+
+func TestValidateContentMaxNodeSize(t *testing.T) {
+	blob := strings.Repeat("QUJD", 1024)
+	content := []Node{
+		{Tag: "p", Children: []interface{}{"Small paragraph"}},
+		{Tag: "figure", Children: []interface{}{
+			Node{Tag: "figcaption", Children: []interface{}{"Caption"}},
+		}},
+		{Tag: "pre", Children: []interface{}{"data:image/png;base64,", Node{Tag: "code", Children: []interface{}{blob}}}},
+	}
+
+	// No limit by default
+	require.NoError(t, ValidateContent(content))
+	require.NoError(t, ValidateContentWithOptions(content, ContentValidationOptions{MaxNodeSize: 8 * 1024}))
+
+	err := ValidateContentWithOptions(content, ContentValidationOptions{MaxNodeSize: 1024})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "content[2].children[1].children[0]: node size of ")
+	assert.Contains(t, err.Error(), "exceeds maximum of 1024")
+}
+
+func TestAsNode(t *testing.T) {
+	data, err := json.Marshal([]interface{}{
+		Node{Tag: "a", Attrs: map[string]string{"href": "/x"}, Children: []interface{}{"link"}},
+		Node{Content: "text"},
+	})
+	require.NoError(t, err)
+	var decoded []interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	node, ok := asNode(decoded[0])
+	require.True(t, ok)
+	assert.Equal(t, Node{Tag: "a", Attrs: map[string]string{"href": "/x"}, Children: []interface{}{"link"}}, node)
+
+	// Text Nodes are marshaled as plain strings, which are not nodes
+	assert.Equal(t, "text", decoded[1])
+	_, ok = asNode(decoded[1])
+	assert.False(t, ok)
+	_, ok = asNode((*Node)(nil))
+	assert.False(t, ok)
+}
+
+func TestClientDryValidateContent(t *testing.T) {
+	var created []CreatePageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/createPage", r.URL.Path)
+
+		var req CreatePageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		created = append(created, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(req.Content) > 0 && req.Content[0].Tag == "script" {
+			json.NewEncoder(w).Encode(APIResponse{Ok: false, Error: "CONTENT_INVALID"})
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: Page{Path: "Content-validation-12-15", Title: req.Title}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	content := []Node{{Tag: "p", Children: []interface{}{"Hello"}}}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL))
+		err := client.DryValidateContent(ctx, "test-token", content)
+		assert.ErrorIs(t, err, ErrDryValidationDisabled)
+		assert.Empty(t, created)
+	})
+
+	client := NewClient(WithBaseURL(server.URL), WithDryValidation())
+
+	t.Run("accepted", func(t *testing.T) {
+		created = nil
+		require.NoError(t, client.DryValidateContent(ctx, "test-token", content))
+		require.Len(t, created, 1)
+		assert.Equal(t, DryValidationTitle, created[0].Title)
+		assert.Equal(t, "test-token", created[0].AccessToken)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		created = nil
+		err := client.DryValidateContent(ctx, "test-token", []Node{{Tag: "script"}})
+		require.Error(t, err)
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Len(t, created, 1)
+	})
+}
@@ -0,0 +1,139 @@
+package telegraph
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Granularity is the interval between points of a views time series
+type Granularity int
+
+const (
+	// GranularityDay reports views per UTC day
+	GranularityDay Granularity = iota
+	// GranularityHour reports views per UTC hour
+	GranularityHour
+)
+
+// ViewsPoint is the number of views of a page in one interval
+type ViewsPoint struct {
+	// Time is the start of the interval in UTC
+	Time time.Time
+	// Views is the number of views during the interval
+	Views int
+}
+
+// hourViewsRequest is a getViews request for one hour. Unlike GetViewsRequest,
+// it always sends the hour, so that the first hour of a day can be requested.
+type hourViewsRequest struct {
+	Path  string `json:"path"`
+	Year  int    `json:"year"`
+	Month int    `json:"month"`
+	Day   int    `json:"day"`
+	Hour  int    `json:"hour"`
+}
+
+// ViewsTimeSeries returns the views of a page for every day or hour between from and to
+//
+// Points start with the interval containing from and end with the interval
+// containing to, both in UTC. One getViews call is made per point, subject to
+// the client's rate limit, so prefer daily granularity for long ranges. If ctx
+// is done or a call fails, no points are returned.
+//
+// Example:
+//
+//	to := time.Now()
+//	points, err := client.ViewsTimeSeries(ctx, "My-Article-12-15", to.AddDate(0, 0, -7), to, telegraph.GranularityDay)
+func (c *Client) ViewsTimeSeries(ctx context.Context, path string, from, to time.Time, granularity Granularity) ([]ViewsPoint, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+
+	var step time.Duration
+	from, to = from.UTC(), to.UTC()
+	switch granularity {
+	case GranularityDay:
+		step = 24 * time.Hour
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	case GranularityHour:
+		step = time.Hour
+		from = from.Truncate(time.Hour)
+	default:
+		return nil, fmt.Errorf("invalid granularity: %d", granularity)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("to must not be before from")
+	}
+
+	var points []ViewsPoint
+	for t := from; !t.After(to); t = t.Add(step) {
+		views, err := c.viewsAt(ctx, path, t, granularity)
+		if err != nil {
+			return nil, fmt.Errorf("views for %s: %w", t.Format(time.RFC3339), err)
+		}
+		points = append(points, ViewsPoint{Time: t, Views: views})
+	}
+	return points, nil
+}
+
+// GetViewsAt gets the number of views for a Telegraph page in the UTC day or
+// hour containing t
+//
+// Unlike GetViews, the year, month, day and hour are derived from t, after
+// converting it to UTC.
+//
+// Example:
+//
+//	yesterday := time.Now().AddDate(0, 0, -1)
+//	views, err := client.GetViewsAt(ctx, "My-Article-12-15", yesterday, telegraph.GranularityDay)
+func (c *Client) GetViewsAt(ctx context.Context, path string, t time.Time, granularity Granularity) (*PageViews, error) {
+	if granularity != GranularityDay && granularity != GranularityHour {
+		return nil, fmt.Errorf("invalid granularity: %d", granularity)
+	}
+
+	views, err := c.viewsAt(ctx, path, t.UTC(), granularity)
+	if err != nil {
+		return nil, err
+	}
+	return &PageViews{Views: views}, nil
+}
+
+// viewsAt returns the views of a page in the day or hour starting at t
+func (c *Client) viewsAt(ctx context.Context, path string, t time.Time, granularity Granularity) (int, error) {
+	req := &GetViewsRequest{
+		Path:  path,
+		Year:  t.Year(),
+		Month: int(t.Month()),
+		Day:   t.Day(),
+	}
+	if granularity == GranularityDay {
+		views, err := c.GetViews(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		return views.Views, nil
+	}
+
+	req.Hour = t.Hour()
+	if err := req.Validate(); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/getViews", &hourViewsRequest{
+		Path:  req.Path,
+		Year:  req.Year,
+		Month: req.Month,
+		Day:   req.Day,
+		Hour:  req.Hour,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var views PageViews
+	if err := c.parseResponse(resp, &views); err != nil {
+		return 0, err
+	}
+	return views.Views, nil
+}
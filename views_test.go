@@ -0,0 +1,164 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientViewsTimeSeries(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getViews", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+
+		// Report the day times ten plus the hour as the number of views
+		views := int(body["day"].(float64)) * 10
+		if hour, ok := body["hour"].(float64); ok {
+			views += int(hour)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: views}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	t.Run("daily", func(t *testing.T) {
+		requests = nil
+		from := time.Date(2024, 2, 28, 15, 30, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+		points, err := client.ViewsTimeSeries(ctx, "Test-12-15", from, to, GranularityDay)
+		require.NoError(t, err)
+		assert.Equal(t, []ViewsPoint{
+			{Time: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), Views: 280},
+			{Time: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), Views: 290},
+			{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Views: 10},
+		}, points)
+
+		require.Len(t, requests, 3)
+		assert.Equal(t, "Test-12-15", requests[0]["path"])
+		assert.Equal(t, float64(2024), requests[0]["year"])
+		assert.Equal(t, float64(2), requests[0]["month"])
+		assert.NotContains(t, requests[0], "hour")
+	})
+
+	t.Run("hourly across midnight", func(t *testing.T) {
+		requests = nil
+		from := time.Date(2024, 3, 1, 23, 10, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 2, 0, 59, 0, 0, time.UTC)
+
+		points, err := client.ViewsTimeSeries(ctx, "Test-12-15", from, to, GranularityHour)
+		require.NoError(t, err)
+		assert.Equal(t, []ViewsPoint{
+			{Time: time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC), Views: 33},
+			{Time: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Views: 20},
+		}, points)
+
+		// The first hour of a day is sent explicitly
+		require.Len(t, requests, 2)
+		assert.Equal(t, float64(0), requests[1]["hour"])
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		now := time.Now()
+		_, err := client.ViewsTimeSeries(ctx, "Test-12-15", now, now.AddDate(0, 0, -1), GranularityDay)
+		assert.EqualError(t, err, "to must not be before from")
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		now := time.Now()
+		points, err := client.ViewsTimeSeries(canceled, "Test-12-15", now.AddDate(0, 0, -2), now, GranularityDay)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, points)
+	})
+}
+
+func TestClientViewsClockBoundaries(t *testing.T) {
+	var req map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/getViews", r.URL.Path)
+
+		req = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIResponse{Ok: true, Result: PageViews{Views: 5}})
+	}))
+	defer server.Close()
+
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name             string
+		now              time.Time
+		year, month, day int
+		hour             int
+	}{
+		{
+			name: "last second of the year",
+			now:  time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
+			year: 2023, month: 12, day: 31, hour: 23,
+		},
+		{
+			name: "first second of the year",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			year: 2024, month: 1, day: 1, hour: 0,
+		},
+		{
+			name: "local new year's eve is already January in UTC",
+			now:  time.Date(2023, 12, 31, 20, 30, 0, 0, newYork),
+			year: 2024, month: 1, day: 1, hour: 1,
+		},
+		{
+			name: "leap day rollover",
+			now:  time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC),
+			year: 2024, month: 2, day: 29, hour: 23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := NewClient(WithBaseURL(server.URL), WithClock(func() time.Time { return tt.now }))
+
+			views, err := client.GetViewsToday(ctx, "Test-12-15")
+			require.NoError(t, err)
+			assert.Equal(t, 5, views.Views)
+			assert.Equal(t, float64(tt.year), req["year"])
+			assert.Equal(t, float64(tt.month), req["month"])
+			assert.Equal(t, float64(tt.day), req["day"])
+			assert.NotContains(t, req, "hour")
+
+			views, err = client.GetViewsAt(ctx, "Test-12-15", tt.now, GranularityHour)
+			require.NoError(t, err)
+			assert.Equal(t, 5, views.Views)
+			assert.Equal(t, float64(tt.year), req["year"])
+			assert.Equal(t, float64(tt.month), req["month"])
+			assert.Equal(t, float64(tt.day), req["day"])
+			assert.Equal(t, float64(tt.hour), req["hour"])
+		})
+	}
+
+	t.Run("invalid granularity", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL))
+		_, err := client.GetViewsAt(context.Background(), "Test-12-15", time.Now(), Granularity(7))
+		assert.EqualError(t, err, "invalid granularity: 7")
+	})
+}